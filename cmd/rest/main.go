@@ -2,27 +2,36 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 	"user-service/config"
+	"user-service/internal/core/interfaces"
 	"user-service/internal/core/services"
 	"user-service/internal/handlers"
-	"user-service/internal/repositories"
-	"user-service/pkg/azure"
 	"user-service/pkg/logging"
 	"user-service/pkg/tracing"
 
-	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
+	"go.opentelemetry.io/otel"
 
 	"github.com/gin-gonic/gin"
 )
 
-const defaultConfig = "./config/local.config"
+const (
+	defaultConfig   = "./config/local.config"
+	shutdownTimeout = 10 * time.Second
+)
 
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	cfgPath := GetEnvOrDefault("config", defaultConfig)
 	cfg, err := config.UseConfig(cfgPath)
 
@@ -43,59 +52,68 @@ func main() {
 	tracer, err := tracing.NewOpenTracing(cfg.Server.Service, cfg.Tracing.Host, cfg.Tracing.Port)
 
 	if err != nil {
-		logger.Warning(context.Background(), "Failed to setup tracing: %v", err)
+		logger.Warning(context.Background(), "Failed to setup tracing", "error", err)
 	}
 
 	//--------------------------------------------------------------------------------------
 	// Setup Database
 	//--------------------------------------------------------------------------------------
 
-	dsn := fmt.Sprintf("host=%s port=%d user=%s "+
-		"password=%s dbname=%s sslmode=%s",
-		cfg.Database.Host, cfg.Database.Port, cfg.Database.User, cfg.Database.Password, cfg.Database.Database, cfg.Database.SSLMode)
-	db, err := gorm.Open(postgres.Open(dsn))
+	store, err := newStorage(cfg, tracer)
 
 	if err != nil {
 		logger.Fatal(context.Background(), err)
 	}
 
-	if cfg.Database.Debug {
-		db.Debug()
-	}
-
-	if tracer != nil {
-		if err = db.Use(otelgorm.NewPlugin(otelgorm.WithTracerProvider(tracer))); err != nil {
-			logger.Fatal(context.Background(), err)
-		}
-	}
-
-	if err != nil {
-		logger.Fatal(context.Background(), err)
-	}
+	//--------------------------------------------------------------------------------------
+	// Setup Message Bus
+	//--------------------------------------------------------------------------------------
 
-	userRepository, err := repositories.NewUserRepository(db)
+	bus, err := newMessageBus(cfg, logger, otel.GetTracerProvider())
 
 	if err != nil {
 		logger.Fatal(context.Background(), err)
 	}
 
 	//--------------------------------------------------------------------------------------
-	// Setup Azure Service Bus
+	// Setup Services
 	//--------------------------------------------------------------------------------------
 
-	azServiceBus, err := azure.NewAzureServiceBus(cfg)
+	userService := services.NewUserService(store.userRepository, services.NewOutboxPublisher(store.outboxRepository))
 
-	if err != nil {
-		logger.Fatal(context.Background(), err)
-	}
+	// workers are the background goroutines using the message bus, which is only
+	// closed once they have stopped.
+	var workers sync.WaitGroup
+
+	outboxRelay := services.NewOutboxRelay(store.outboxRepository, bus.publisher, logger, cfg)
 
-	azPublisher := services.NewAzurePublisher(azServiceBus, cfg)
+	workers.Add(1)
+
+	go func() {
+		defer workers.Done()
+		outboxRelay.Run(ctx)
+	}()
 
 	//--------------------------------------------------------------------------------------
-	// Setup Services
+	// Setup Consumers
 	//--------------------------------------------------------------------------------------
 
-	userService := services.NewUserService(userRepository, azPublisher)
+	var deadLetters interfaces.DeadLetterQueue
+
+	if cfg.Consumer.Enabled && bus.newConsumer == nil {
+		logger.Warning(context.Background(), "identity events are not consumed by this message bus", "publisher", cfg.MessageBus.Publisher)
+	} else if cfg.Consumer.Enabled {
+		var identityConsumer consumer
+
+		identityConsumer, deadLetters = bus.newConsumer(handlers.NewIdentityEventHandler(userService, logger))
+
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+			identityConsumer.Run(ctx)
+		}()
+	}
 
 	//--------------------------------------------------------------------------------------
 	// Setup HTTP server
@@ -111,8 +129,56 @@ func main() {
 	deliveryHandler.SetupEndpoints()
 	deliveryHandler.SetupSwagger()
 	deliveryHandler.SetupHealthprobe()
+	deliveryHandler.SetupOutboxDeadLetterEndpoints(services.NewOutboxDeadLetters(store.outboxRepository))
+
+	if deadLetters != nil {
+		deliveryHandler.SetupDeadLetterEndpoints(deadLetters)
+	}
+
+	if bus.events != nil {
+		deliveryHandler.SetupDebugEndpoints(bus.events)
+	}
+
+	server := &http.Server{Addr: cfg.Server.Port, Handler: router}
+	serverErrors := make(chan error, 1)
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrors <- err
+		}
+	}()
+
+	//--------------------------------------------------------------------------------------
+	// Shutdown
+	//--------------------------------------------------------------------------------------
+
+	exitCode := 0
+
+	select {
+	case <-ctx.Done():
+	case err = <-serverErrors:
+		logger.Error(context.Background(), "http server failed", "error", err)
+		exitCode = 1
+	}
+
+	// Stops the outbox relay and the consumers when the server failed.
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
 
-	logger.Fatal(context.Background(), router.Run(cfg.Server.Port))
+	if err = server.Shutdown(shutdownCtx); err != nil {
+		logger.Error(context.Background(), "shutting down http server failed", "error", err)
+	}
+
+	workers.Wait()
+
+	bus.close()
+
+	if exitCode != 0 {
+		cancel()
+		os.Exit(exitCode)
+	}
 }
 
 func GetEnvOrDefault(environmentKey, defaultValue string) string {
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"user-service/config"
+	"user-service/internal/core/interfaces"
+	"user-service/internal/core/services"
+	"user-service/internal/handlers"
+	"user-service/pkg/azure"
+	"user-service/pkg/jetstream"
+	"user-service/pkg/kafka"
+	"user-service/pkg/logging"
+	"user-service/pkg/rabbitmq"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Publishers selectable with messageBus.publisher.
+const (
+	publisherRabbitMQ = "rabbitmq"
+	publisherAzure    = "azure"
+	publisherNATS     = "nats"
+	publisherKafka    = "kafka"
+	publisherLog      = "log"
+	publisherMemory   = "memory"
+	publisherNone     = "none"
+)
+
+type consumer interface {
+	Run(ctx context.Context)
+}
+
+// messageBus is the publisher selected by the configuration. Only RabbitMQ and
+// Azure Service Bus deliver the identity events, newConsumer is nil otherwise.
+// events is only set for the in-memory publisher.
+type messageBus struct {
+	publisher   interfaces.MessageBusPublisher
+	newConsumer func(handler *handlers.IdentityEventHandler) (consumer, interfaces.DeadLetterQueue)
+	events      interfaces.PublishedEvents
+	close       func()
+}
+
+func newMessageBus(cfg *config.Config, logger logging.Logger, tracerProvider trace.TracerProvider) (*messageBus, error) {
+	switch cfg.MessageBus.Publisher {
+	case publisherRabbitMQ:
+		rmqServer, err := rabbitmq.NewRabbitMQ(cfg, logger)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &messageBus{
+			publisher: services.NewRabbitMQPublisher(rmqServer, tracerProvider, cfg),
+			newConsumer: func(handler *handlers.IdentityEventHandler) (consumer, interfaces.DeadLetterQueue) {
+				return handlers.NewRabbitMQConsumer(rmqServer, handler, logger, cfg), handlers.NewRabbitMQDeadLetters(rmqServer, handler, cfg)
+			},
+			close: rmqServer.Close,
+		}, nil
+	case publisherAzure:
+		azServiceBus, err := azure.NewAzureServiceBus(cfg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &messageBus{
+			publisher: services.NewAzurePublisher(azServiceBus, tracerProvider, cfg),
+			newConsumer: func(handler *handlers.IdentityEventHandler) (consumer, interfaces.DeadLetterQueue) {
+				return handlers.NewAzureConsumer(azServiceBus.Client, handler, logger, cfg), handlers.NewAzureDeadLetters(azServiceBus, handler, cfg)
+			},
+			close: azServiceBus.Close,
+		}, nil
+	case publisherNATS:
+		natsServer, err := jetstream.NewJetStream(cfg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &messageBus{publisher: services.NewNATSPublisher(natsServer, tracerProvider, cfg), close: natsServer.Close}, nil
+	case publisherKafka:
+		kafkaServer, err := kafka.NewKafka(cfg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &messageBus{publisher: services.NewKafkaPublisher(kafkaServer, tracerProvider, cfg), close: kafkaServer.Close}, nil
+	case publisherLog:
+		return &messageBus{publisher: services.NewLogPublisher(logger, cfg), close: func() {}}, nil
+	case publisherMemory:
+		memoryPublisher := services.NewMemoryPublisher(cfg)
+
+		return &messageBus{publisher: memoryPublisher, events: memoryPublisher, close: func() {}}, nil
+	case publisherNone:
+		return &messageBus{publisher: services.NewNoopPublisher(), close: func() {}}, nil
+	default:
+		return nil, fmt.Errorf("unknown message bus publisher %q", cfg.MessageBus.Publisher)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"user-service/config"
+	"user-service/internal/core/interfaces"
+	"user-service/internal/repositories"
+
+	"github.com/glebarez/sqlite"
+	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Storage drivers selectable with database.driver.
+const (
+	driverPostgres = "postgres"
+	driverMemory   = "memory"
+	driverSQLite   = "sqlite"
+)
+
+// storage holds the repositories of the selected driver. The in-memory driver
+// loses its data on restart and is meant for local development and tests. The
+// SQLite driver keeps the data in a single file for demo installs and CI runs;
+// it is written in pure Go, so it also works in the static release binaries.
+type storage struct {
+	userRepository   interfaces.UserRepository
+	outboxRepository interfaces.OutboxRepository
+}
+
+func newStorage(cfg *config.Config, tracer *trace.TracerProvider) (*storage, error) {
+	switch cfg.Database.Driver {
+	case driverPostgres, "":
+		dsn := fmt.Sprintf("host=%s port=%d user=%s "+
+			"password=%s dbname=%s sslmode=%s",
+			cfg.Database.Host, cfg.Database.Port, cfg.Database.User, cfg.Database.Password, cfg.Database.Database, cfg.Database.SSLMode)
+
+		return newGormStorage(postgres.Open(dsn), cfg, tracer)
+	case driverMemory:
+		store := repositories.NewMemoryStore()
+
+		return &storage{
+			userRepository:   repositories.NewMemoryUserRepository(store),
+			outboxRepository: repositories.NewMemoryOutboxRepository(store),
+		}, nil
+	case driverSQLite:
+		// Writers wait for each other instead of failing with "database is locked".
+		dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)", cfg.Database.Path)
+
+		return newGormStorage(sqlite.Open(dsn), cfg, tracer)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
+	}
+}
+
+func newGormStorage(dialector gorm.Dialector, cfg *config.Config, tracer *trace.TracerProvider) (*storage, error) {
+	db, err := gorm.Open(dialector)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database.Debug {
+		db.Debug()
+	}
+
+	if tracer != nil {
+		if err = db.Use(otelgorm.NewPlugin(otelgorm.WithTracerProvider(tracer))); err != nil {
+			return nil, err
+		}
+	}
+
+	userRepository, err := repositories.NewUserRepository(db)
+
+	if err != nil {
+		return nil, err
+	}
+
+	outboxRepository, err := repositories.NewOutboxRepository(db)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage{userRepository: userRepository, outboxRepository: outboxRepository}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
@@ -16,6 +17,12 @@ type Config struct {
 	Database        Database
 	Tracing         Tracing
 	AzureServiceBus AzureServiceBus
+	Kafka           Kafka
+	NATS            NATS
+	MessageBus      MessageBus
+	Outbox          Outbox
+	Events          Events
+	Consumer        Consumer
 }
 
 type Server struct {
@@ -25,18 +32,40 @@ type Server struct {
 }
 
 type RabbitMQ struct {
-	Host     string
-	Port     int
-	User     string
-	Password string
-	Exchange string
+	Host                string
+	Port                int
+	User                string
+	Password            string
+	Exchange            string
+	ConfirmTimeout      time.Duration
+	Mandatory           bool
+	ReconnectBackoff    time.Duration
+	MaxReconnectBackoff time.Duration
 }
 
 type AzureServiceBus struct {
 	ConnectionString string
 }
 
+type Kafka struct {
+	Brokers        []string
+	ClientID       string
+	ProduceTimeout time.Duration
+}
+
+type NATS struct {
+	URL             string
+	Stream          string
+	DuplicateWindow time.Duration
+	PublishTimeout  time.Duration
+}
+
+type MessageBus struct {
+	Publisher string
+}
+
 type Database struct {
+	Driver   string
 	Host     string
 	Port     int
 	User     string
@@ -44,6 +73,31 @@ type Database struct {
 	Database string
 	Debug    bool
 	SSLMode  string
+	Path     string
+}
+
+type Outbox struct {
+	PollInterval    time.Duration
+	BatchSize       int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	MaxAttempts     int
+	ClaimTimeout    time.Duration
+	DeadRetention   time.Duration
+}
+
+type Events struct {
+	Source    string
+	Mode      string
+	SchemaURL string
+}
+
+type Consumer struct {
+	Enabled       bool
+	Queue         string
+	Subscription  string
+	Prefetch      int
+	MaxDeliveries int
 }
 
 type Tracing struct {
@@ -62,9 +116,25 @@ func initDefaultValues() *Config {
 	defaultConfig.RabbitMQ.User = "user"
 	defaultConfig.RabbitMQ.Password = "password"
 	defaultConfig.RabbitMQ.Exchange = "topics"
+	defaultConfig.RabbitMQ.ConfirmTimeout = 5 * time.Second
+	defaultConfig.RabbitMQ.Mandatory = true
+	defaultConfig.RabbitMQ.ReconnectBackoff = time.Second
+	defaultConfig.RabbitMQ.MaxReconnectBackoff = 30 * time.Second
 
 	defaultConfig.AzureServiceBus.ConnectionString = "Endpoint=sb://servicebus.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=yourkey"
 
+	defaultConfig.Kafka.Brokers = []string{"localhost:9092"}
+	defaultConfig.Kafka.ClientID = "user-service"
+	defaultConfig.Kafka.ProduceTimeout = 10 * time.Second
+
+	defaultConfig.NATS.URL = "nats://localhost:4222"
+	defaultConfig.NATS.Stream = "USERS"
+	defaultConfig.NATS.DuplicateWindow = 2 * time.Minute
+	defaultConfig.NATS.PublishTimeout = 5 * time.Second
+
+	defaultConfig.MessageBus.Publisher = "azure"
+
+	defaultConfig.Database.Driver = "postgres"
 	defaultConfig.Database.Host = "localhost"
 	defaultConfig.Database.Port = 5432
 	defaultConfig.Database.User = "user"
@@ -72,6 +142,25 @@ func initDefaultValues() *Config {
 	defaultConfig.Database.Database = "user"
 	defaultConfig.Database.Debug = false
 	defaultConfig.Database.SSLMode = "disable"
+	defaultConfig.Database.Path = "user.db"
+
+	defaultConfig.Outbox.PollInterval = time.Second
+	defaultConfig.Outbox.BatchSize = 100
+	defaultConfig.Outbox.RetryBackoff = time.Second
+	defaultConfig.Outbox.MaxRetryBackoff = 5 * time.Minute
+	defaultConfig.Outbox.MaxAttempts = 25
+	defaultConfig.Outbox.ClaimTimeout = time.Minute
+	defaultConfig.Outbox.DeadRetention = 7 * 24 * time.Hour
+
+	defaultConfig.Events.Source = "/bikepack/user-service"
+	defaultConfig.Events.Mode = "binary"
+	defaultConfig.Events.SchemaURL = ""
+
+	defaultConfig.Consumer.Enabled = false
+	defaultConfig.Consumer.Queue = "user-service.identity"
+	defaultConfig.Consumer.Subscription = "user-service"
+	defaultConfig.Consumer.Prefetch = 10
+	defaultConfig.Consumer.MaxDeliveries = 5
 
 	defaultConfig.Tracing.Host = ""
 	defaultConfig.Tracing.Port = 0
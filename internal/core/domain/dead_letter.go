@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// DeadLetter is a consumed message that could not be handled.
+type DeadLetter struct {
+	ID          string
+	Type        string
+	Reason      string
+	Description string
+	Attempts    int
+	FailedAt    *time.Time
+	ContentType string
+	Body        []byte
+}
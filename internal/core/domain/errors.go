@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrValidation         = errors.New("validation failed")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrForbidden          = errors.New("forbidden")
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Field: field, Message: message}
+}
+
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Field, err.Message)
+}
+
+func (err *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+type NotFoundError struct {
+	Resource string
+	ID       string
+}
+
+func NewNotFoundError(resource, id string) *NotFoundError {
+	return &NotFoundError{Resource: resource, ID: id}
+}
+
+func (err *NotFoundError) Error() string {
+	return fmt.Sprintf("%s with id %s not found", err.Resource, err.ID)
+}
+
+func (err *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+type ConflictError struct {
+	Field   string
+	Message string
+}
+
+func NewConflictError(field, message string) *ConflictError {
+	return &ConflictError{Field: field, Message: message}
+}
+
+func (err *ConflictError) Error() string {
+	if err.Field == "" {
+		return err.Message
+	}
+
+	return fmt.Sprintf("%s: %s", err.Field, err.Message)
+}
+
+func (err *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+type ForbiddenError struct {
+	Message string
+}
+
+func NewForbiddenError(message string) *ForbiddenError {
+	return &ForbiddenError{Message: message}
+}
+
+func (err *ForbiddenError) Error() string {
+	return err.Message
+}
+
+func (err *ForbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+type PreconditionFailedError struct {
+	Message string
+}
+
+func NewPreconditionFailedError(message string) *PreconditionFailedError {
+	return &PreconditionFailedError{Message: message}
+}
+
+func (err *PreconditionFailedError) Error() string {
+	return err.Message
+}
+
+func (err *PreconditionFailedError) Is(target error) bool {
+	return target == ErrPreconditionFailed
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ErrorsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ErrorsTestSuite) TestErrors_Is() {
+	suite.ErrorIs(NewValidationError("email", "email is not valid"), ErrValidation)
+	suite.ErrorIs(NewNotFoundError("user", "test-id"), ErrNotFound)
+	suite.ErrorIs(NewConflictError("email", "email is already in use"), ErrConflict)
+	suite.ErrorIs(NewForbiddenError("not allowed"), ErrForbidden)
+	suite.ErrorIs(NewPreconditionFailedError("user has been modified"), ErrPreconditionFailed)
+}
+
+func (suite *ErrorsTestSuite) TestErrors_Wrapped() {
+	err := fmt.Errorf("getting user: %w", NewNotFoundError("user", "test-id"))
+
+	var notFoundErr *NotFoundError
+	suite.True(errors.As(err, &notFoundErr))
+	suite.Equal("test-id", notFoundErr.ID)
+	suite.ErrorIs(err, ErrNotFound)
+	suite.False(errors.Is(err, ErrConflict))
+}
+
+func TestUnit_ErrorsTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorsTestSuite))
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	UserCreatedEvent = "user.create"
+	UserUpdatedEvent = "user.update"
+	UserDeletedEvent = "user.delete"
+	UserErasedEvent  = "user.erased"
+)
+
+// OutboxMessage is a user event that has been committed together with the
+// change that caused it and is waiting to be relayed to the message bus. The
+// W3C trace context of the change is kept so the relayed message joins its trace.
+// A message that can not be delivered is kept with DeadAt set and no longer
+// holds back the later messages of its user. ClaimedUntil is set while a relay
+// is publishing the message.
+type OutboxMessage struct {
+	ID            uint64 `gorm:"primaryKey;autoIncrement"`
+	AggregateID   string `gorm:"index;not null"`
+	Type          string `gorm:"not null"`
+	Payload       []byte `gorm:"not null"`
+	Attempts      int    `gorm:"not null;default:0"`
+	LastError     string
+	TraceParent   string
+	TraceState    string
+	NextAttemptAt time.Time  `gorm:"index;not null"`
+	ClaimedUntil  *time.Time `gorm:"index"`
+	DeadAt        *time.Time `gorm:"index"`
+	CreatedAt     time.Time  `gorm:"not null"`
+}
+
+func NewOutboxMessage(eventType string, user User, at time.Time) (OutboxMessage, error) {
+	payload, err := json.Marshal(user)
+
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+
+	return OutboxMessage{
+		AggregateID:   user.ID,
+		Type:          eventType,
+		Payload:       payload,
+		NextAttemptAt: at,
+		CreatedAt:     at,
+	}, nil
+}
+
+func (message OutboxMessage) User() (User, error) {
+	var user User
+
+	err := json.Unmarshal(message.Payload, &user)
+
+	return user, err
+}
+
+// IsDue reports whether the message may be attempted at the given time.
+func (message OutboxMessage) IsDue(at time.Time) bool {
+	return !message.NextAttemptAt.After(at)
+}
+
+// IsClaimed reports whether a relay has claimed the message at the given time.
+func (message OutboxMessage) IsClaimed(at time.Time) bool {
+	return message.ClaimedUntil != nil && message.ClaimedUntil.After(at)
+}
+
+// RetryAt returns when the next attempt should be made after a failure,
+// doubling the backoff for every previous attempt up to the maximum.
+func (message OutboxMessage) RetryAt(at time.Time, backoff, maxBackoff time.Duration) time.Time {
+	delay := backoff
+
+	for i := 0; i < message.Attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	return at.Add(delay)
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type OutboxTestSuite struct {
+	suite.Suite
+}
+
+func (suite *OutboxTestSuite) TestOutbox_NewOutboxMessage() {
+	user := User{ID: "test-id", Name: "test-name", LastName: "test-lastname", Email: "test@test.com", Version: 1}
+	at := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	message, err := NewOutboxMessage(UserCreatedEvent, user, at)
+
+	suite.NoError(err)
+	suite.Equal("test-id", message.AggregateID)
+	suite.Equal(UserCreatedEvent, message.Type)
+	suite.True(message.IsDue(at))
+
+	decoded, err := message.User()
+
+	suite.NoError(err)
+	suite.Equal(user, decoded)
+}
+
+func (suite *OutboxTestSuite) TestOutbox_IsDue() {
+	at := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	message := OutboxMessage{NextAttemptAt: at.Add(time.Second)}
+
+	suite.False(message.IsDue(at))
+	suite.True(message.IsDue(at.Add(time.Second)))
+}
+
+func (suite *OutboxTestSuite) TestOutbox_IsClaimed() {
+	at := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := at.Add(time.Minute)
+
+	suite.False(OutboxMessage{}.IsClaimed(at))
+	suite.True(OutboxMessage{ClaimedUntil: &until}.IsClaimed(at))
+	suite.False(OutboxMessage{ClaimedUntil: &until}.IsClaimed(until))
+}
+
+func (suite *OutboxTestSuite) TestOutbox_RetryAt() {
+	at := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	suite.Equal(at.Add(time.Second), OutboxMessage{Attempts: 0}.RetryAt(at, time.Second, time.Minute))
+	suite.Equal(at.Add(8*time.Second), OutboxMessage{Attempts: 3}.RetryAt(at, time.Second, time.Minute))
+	suite.Equal(at.Add(time.Minute), OutboxMessage{Attempts: 30}.RetryAt(at, time.Second, time.Minute))
+}
+
+func TestUnit_OutboxTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxTestSuite))
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// PublishedEvent is an event handed to the message bus.
+type PublishedEvent struct {
+	Topic   string
+	ID      string
+	Type    string
+	Subject string
+	Time    time.Time
+	Data    []byte
+}
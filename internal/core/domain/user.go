@@ -1,43 +1,119 @@
 package domain
 
 import (
-	"errors"
+	"crypto/sha256"
+	"encoding/hex"
 	"regexp"
 	"strings"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type User struct {
-	ID       string
-	Name     string
-	LastName string
-	Email    string
+	ID        string
+	Name      string
+	LastName  string
+	Email     string
+	ErasedAt  *time.Time
+	ErasedBy  string
+	Version   int64          `gorm:"not null;default:1"`
+	CreatedAt time.Time      `gorm:"index;default:CURRENT_TIMESTAMP"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
+var (
+	emailReg = regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`)
+	nameReg  = regexp.MustCompile(`^[A-Za-z]+(([,.] |[ '-])[A-Za-z]+)*([.,'-]?)$`)
+)
+
 func NewUser(id, name, lastName, email string) (User, error) {
-	if id == "" || name == "" || lastName == "" || email == "" {
-		return User{}, errors.New("missing data")
+	if id == "" {
+		return User{}, NewValidationError("id", "id is required")
 	}
 
-	emailReg := regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`)
+	user := User{ID: id, Version: 1}
 
-	if !emailReg.MatchString(email) {
-		return User{}, errors.New("email is not valid")
+	if err := user.ChangeName(name, lastName); err != nil {
+		return User{}, err
+	}
+
+	if err := user.ChangeEmail(email); err != nil {
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+func (user *User) ChangeName(name, lastName string) error {
+	if name == "" {
+		return NewValidationError("name", "name is required")
 	}
 
-	nameReg := regexp.MustCompile(`^[A-Za-z]+(([,.] |[ '-])[A-Za-z]+)*([.,'-]?)$`)
+	if lastName == "" {
+		return NewValidationError("last_name", "last name is required")
+	}
 
 	if !nameReg.MatchString(name) {
-		return User{}, errors.New("name not allowed")
+		return NewValidationError("name", "name not allowed")
 	}
 
 	if !nameReg.MatchString(lastName) {
-		return User{}, errors.New("last name not allowed")
+		return NewValidationError("last_name", "last name not allowed")
+	}
+
+	user.Name = strings.ToLower(name)
+	user.LastName = strings.ToLower(lastName)
+
+	return nil
+}
+
+func (user *User) ChangeEmail(email string) error {
+	if email == "" {
+		return NewValidationError("email", "email is required")
+	}
+
+	if !emailReg.MatchString(email) {
+		return NewValidationError("email", "email is not valid")
+	}
+
+	user.Email = strings.ToLower(email)
+
+	return nil
+}
+
+func (user *User) IsErased() bool {
+	return user.ErasedAt != nil
+}
+
+// Erase irreversibly replaces the personal data of the user with pseudonyms derived from its ID.
+// The ID itself is kept so references from other services stay valid.
+func (user *User) Erase(requestedBy string, at time.Time) {
+	hash := sha256.Sum256([]byte(user.ID))
+	pseudonym := hex.EncodeToString(hash[:8])
+
+	letters := make([]byte, len(pseudonym))
+	for i, c := range pseudonym {
+		if c >= '0' && c <= '9' {
+			letters[i] = byte('a' + c - '0')
+		} else {
+			letters[i] = byte('k' + c - 'a')
+		}
+	}
+
+	user.Name = "erased"
+	user.LastName = string(letters)
+	user.Email = "erased-" + pseudonym + "@erased.test"
+	user.ErasedAt = &at
+	user.ErasedBy = requestedBy
+}
+
+// CheckVersion verifies that the user has not been modified since the given version was read.
+// A version of 0 skips the check.
+func (user *User) CheckVersion(version int64) error {
+	if version != 0 && version != user.Version {
+		return NewPreconditionFailedError("user has been modified")
 	}
 
-	return User{
-		ID:       id,
-		Name:     strings.ToLower(name),
-		LastName: strings.ToLower(lastName),
-		Email:    strings.ToLower(email),
-	}, nil
+	return nil
 }
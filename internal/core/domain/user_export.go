@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+type UserExport struct {
+	User       User
+	ExportedAt time.Time
+}
+
+func NewUserExport(user User, exportedAt time.Time) UserExport {
+	return UserExport{
+		User:       user,
+		ExportedAt: exportedAt,
+	}
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	SortByCreatedAt = "created_at"
+	SortByName      = "name"
+	SortByLastName  = "last_name"
+	SortByEmail     = "email"
+
+	DefaultPageSize = 25
+	MaxPageSize     = 100
+)
+
+type UserCursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+type UserQuery struct {
+	Limit      int
+	Cursor     *UserCursor
+	Sort       string
+	Descending bool
+	Email      string
+	NamePrefix string
+}
+
+type UserPage struct {
+	Users      []User
+	NextCursor string
+	Total      int64
+}
+
+// NewUserQuery validates the paging, sorting and filter options for listing users.
+// The sort field can be prefixed with a '-' to sort in descending order.
+func NewUserQuery(limit int, cursor, sort, email, namePrefix string) (UserQuery, error) {
+	query := UserQuery{
+		Limit:      limit,
+		Sort:       SortByCreatedAt,
+		Email:      strings.ToLower(email),
+		NamePrefix: strings.ToLower(namePrefix),
+	}
+
+	if limit < 0 {
+		return UserQuery{}, NewValidationError("limit", "limit can not be negative")
+	}
+
+	if limit == 0 {
+		query.Limit = DefaultPageSize
+	}
+
+	if limit > MaxPageSize {
+		query.Limit = MaxPageSize
+	}
+
+	if sort != "" {
+		query.Descending = strings.HasPrefix(sort, "-")
+		query.Sort = strings.TrimPrefix(sort, "-")
+	}
+
+	switch query.Sort {
+	case SortByCreatedAt, SortByName, SortByLastName, SortByEmail:
+	default:
+		return UserQuery{}, NewValidationError("sort", "sort field not allowed")
+	}
+
+	if cursor != "" {
+		decoded, err := DecodeUserCursor(cursor)
+
+		if err != nil {
+			return UserQuery{}, err
+		}
+
+		query.Cursor = &decoded
+	}
+
+	return query, nil
+}
+
+// CursorValue returns the value of the cursor typed to match the sorted column.
+func (query UserQuery) CursorValue() (interface{}, error) {
+	if query.Cursor == nil {
+		return nil, nil
+	}
+
+	if query.Sort == SortByCreatedAt {
+		value, err := time.Parse(time.RFC3339Nano, query.Cursor.Value)
+
+		if err != nil {
+			return nil, NewValidationError("cursor", "cursor is not valid")
+		}
+
+		return value, nil
+	}
+
+	return query.Cursor.Value, nil
+}
+
+func (user User) SortValue(field string) string {
+	switch field {
+	case SortByName:
+		return user.Name
+	case SortByLastName:
+		return user.LastName
+	case SortByEmail:
+		return user.Email
+	default:
+		return user.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+func EncodeUserCursor(user User, sort string) string {
+	js, _ := json.Marshal(UserCursor{Value: user.SortValue(sort), ID: user.ID})
+	return base64.RawURLEncoding.EncodeToString(js)
+}
+
+func DecodeUserCursor(cursor string) (UserCursor, error) {
+	js, err := base64.RawURLEncoding.DecodeString(cursor)
+
+	if err != nil {
+		return UserCursor{}, NewValidationError("cursor", "cursor is not valid")
+	}
+
+	var decoded UserCursor
+
+	if err = json.Unmarshal(js, &decoded); err != nil || decoded.ID == "" {
+		return UserCursor{}, NewValidationError("cursor", "cursor is not valid")
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type UserQueryTestSuite struct {
+	suite.Suite
+}
+
+func (suite *UserQueryTestSuite) TestUserQuery_NewUserQuery_Defaults() {
+	query, err := NewUserQuery(0, "", "", "", "")
+
+	suite.NoError(err)
+
+	suite.Equal(DefaultPageSize, query.Limit)
+	suite.Equal(SortByCreatedAt, query.Sort)
+	suite.False(query.Descending)
+	suite.Nil(query.Cursor)
+}
+
+func (suite *UserQueryTestSuite) TestUserQuery_NewUserQuery_Descending() {
+	query, err := NewUserQuery(10, "", "-last_name", "Test@Email.com", "Te")
+
+	suite.NoError(err)
+
+	suite.Equal(10, query.Limit)
+	suite.Equal(SortByLastName, query.Sort)
+	suite.True(query.Descending)
+	suite.Equal("test@email.com", query.Email)
+	suite.Equal("te", query.NamePrefix)
+}
+
+func (suite *UserQueryTestSuite) TestUserQuery_NewUserQuery_MaxLimit() {
+	query, err := NewUserQuery(MaxPageSize+1, "", "", "", "")
+
+	suite.NoError(err)
+
+	suite.Equal(MaxPageSize, query.Limit)
+}
+
+func (suite *UserQueryTestSuite) TestUserQuery_NewUserQuery_InvalidSort() {
+	_, err := NewUserQuery(0, "", "id; DROP TABLE users", "", "")
+
+	suite.Error(err)
+}
+
+func (suite *UserQueryTestSuite) TestUserQuery_NewUserQuery_InvalidCursor() {
+	_, err := NewUserQuery(0, "not-a-cursor", "", "", "")
+
+	suite.Error(err)
+}
+
+func (suite *UserQueryTestSuite) TestUserQuery_Cursor() {
+	user := User{ID: "test-id", Name: "test", CreatedAt: time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)}
+
+	query, err := NewUserQuery(0, EncodeUserCursor(user, SortByCreatedAt), "", "", "")
+
+	suite.NoError(err)
+
+	suite.Equal(user.ID, query.Cursor.ID)
+
+	value, err := query.CursorValue()
+
+	suite.NoError(err)
+	suite.Equal(user.CreatedAt, value)
+}
+
+func TestUnit_UserQueryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserQueryTestSuite))
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "strings"
+
+type SearchPage struct {
+	Number int
+	Size   int
+}
+
+type UserSearchResult struct {
+	Users []User
+	Total int64
+}
+
+func NewSearchQuery(query string) (string, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	if query == "" {
+		return "", NewValidationError("q", "search query can not be empty")
+	}
+
+	return query, nil
+}
+
+// NewSearchPage validates the requested page of search results. Pages start at 1.
+func NewSearchPage(number, size int) (SearchPage, error) {
+	if number < 0 || size < 0 {
+		return SearchPage{}, NewValidationError("page", "page can not be negative")
+	}
+
+	page := SearchPage{Number: number, Size: size}
+
+	if number == 0 {
+		page.Number = 1
+	}
+
+	if size == 0 {
+		page.Size = DefaultPageSize
+	}
+
+	if size > MaxPageSize {
+		page.Size = MaxPageSize
+	}
+
+	return page, nil
+}
+
+func (page SearchPage) Offset() int {
+	return (page.Number - 1) * page.Size
+}
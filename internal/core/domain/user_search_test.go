@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type UserSearchTestSuite struct {
+	suite.Suite
+}
+
+func (suite *UserSearchTestSuite) TestUserSearch_NewSearchQuery() {
+	query, err := NewSearchQuery("  Test ")
+
+	suite.NoError(err)
+	suite.Equal("test", query)
+}
+
+func (suite *UserSearchTestSuite) TestUserSearch_NewSearchQuery_Empty() {
+	_, err := NewSearchQuery(" ")
+
+	suite.Error(err)
+}
+
+func (suite *UserSearchTestSuite) TestUserSearch_NewSearchPage() {
+	page, err := NewSearchPage(3, 10)
+
+	suite.NoError(err)
+	suite.Equal(20, page.Offset())
+}
+
+func (suite *UserSearchTestSuite) TestUserSearch_NewSearchPage_Defaults() {
+	page, err := NewSearchPage(0, 0)
+
+	suite.NoError(err)
+	suite.Equal(1, page.Number)
+	suite.Equal(DefaultPageSize, page.Size)
+	suite.Equal(0, page.Offset())
+}
+
+func (suite *UserSearchTestSuite) TestUserSearch_NewSearchPage_Negative() {
+	_, err := NewSearchPage(-1, 10)
+
+	suite.Error(err)
+}
+
+func TestUnit_UserSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(UserSearchTestSuite))
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 type Suite struct {
@@ -17,6 +18,7 @@ func (s *Suite) SetupSuite() {
 		Name:     "test-name",
 		LastName: "test-lastname",
 		Email:    "test@test.com",
+		Version:  1,
 	}
 }
 
@@ -58,3 +60,99 @@ func (s *Suite) TestUser_NewUserInvalidEmail() {
 	assert.Error(s.T(), err)
 	assert.Equal(s.T(), User{}, res)
 }
+
+func (s *Suite) TestUser_NewUserValidationError() {
+	_, err := NewUser(s.user.ID, s.user.Name, s.user.LastName, "test")
+
+	var validationErr *ValidationError
+	assert.ErrorAs(s.T(), err, &validationErr)
+	assert.Equal(s.T(), "email", validationErr.Field)
+}
+
+func (s *Suite) TestUser_ChangeName() {
+	user := *s.user
+
+	err := user.ChangeName("New-Name", "New-Lastname")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "new-name", user.Name)
+	assert.Equal(s.T(), "new-lastname", user.LastName)
+}
+
+func (s *Suite) TestUser_ChangeName_Invalid() {
+	user := *s.user
+
+	err := user.ChangeName(s.user.Name, "2222")
+
+	var validationErr *ValidationError
+	assert.ErrorAs(s.T(), err, &validationErr)
+	assert.Equal(s.T(), "last_name", validationErr.Field)
+	assert.Equal(s.T(), *s.user, user)
+}
+
+func (s *Suite) TestUser_ChangeName_Missing() {
+	user := *s.user
+
+	err := user.ChangeName("", s.user.LastName)
+
+	var validationErr *ValidationError
+	assert.ErrorAs(s.T(), err, &validationErr)
+	assert.Equal(s.T(), "name", validationErr.Field)
+	assert.Equal(s.T(), *s.user, user)
+}
+
+func (s *Suite) TestUser_ChangeEmail() {
+	user := *s.user
+
+	err := user.ChangeEmail("new@test.com")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "new@test.com", user.Email)
+}
+
+func (s *Suite) TestUser_ChangeEmail_Invalid() {
+	user := *s.user
+
+	err := user.ChangeEmail("not-an-email")
+
+	var validationErr *ValidationError
+	assert.ErrorAs(s.T(), err, &validationErr)
+	assert.Equal(s.T(), "email", validationErr.Field)
+	assert.Equal(s.T(), *s.user, user)
+}
+
+func (s *Suite) TestUser_CheckVersion() {
+	assert.NoError(s.T(), s.user.CheckVersion(0))
+	assert.NoError(s.T(), s.user.CheckVersion(s.user.Version))
+	assert.ErrorIs(s.T(), s.user.CheckVersion(s.user.Version+1), ErrPreconditionFailed)
+}
+
+func (s *Suite) TestUser_Erase() {
+	user := *s.user
+	at := time.Now()
+
+	user.Erase("admin-id", at)
+
+	assert.Equal(s.T(), s.user.ID, user.ID)
+	assert.NotEqual(s.T(), s.user.Name, user.Name)
+	assert.NotEqual(s.T(), s.user.LastName, user.LastName)
+	assert.NotEqual(s.T(), s.user.Email, user.Email)
+	assert.Equal(s.T(), "admin-id", user.ErasedBy)
+	assert.Equal(s.T(), &at, user.ErasedAt)
+	assert.True(s.T(), user.IsErased())
+
+	_, err := NewUser(user.ID, user.Name, user.LastName, user.Email)
+	assert.NoError(s.T(), err)
+}
+
+func (s *Suite) TestUser_Erase_Deterministic() {
+	first := *s.user
+	second := *s.user
+
+	first.Erase("admin-id", time.Now())
+	second.Erase("other-id", time.Now())
+
+	assert.Equal(s.T(), first.Name, second.Name)
+	assert.Equal(s.T(), first.LastName, second.LastName)
+	assert.Equal(s.T(), first.Email, second.Email)
+}
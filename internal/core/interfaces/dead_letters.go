@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"user-service/internal/core/domain"
+)
+
+type DeadLetterQueue interface {
+	List(ctx context.Context, limit int) ([]domain.DeadLetter, error)
+	Get(ctx context.Context, id string) (domain.DeadLetter, error)
+	Replay(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string) error
+	Purge(ctx context.Context) (int, error)
+}
@@ -8,4 +8,13 @@ import (
 type MessageBusPublisher interface {
 	CreateUser(ctx context.Context, user domain.User) error
 	UpdateUserDetails(ctx context.Context, user domain.User) error
+	DeleteUser(ctx context.Context, user domain.User) error
+	EraseUser(ctx context.Context, user domain.User) error
+}
+
+// PublishedEvents gives access to the events of a publisher that keeps them in
+// memory. Subscriptions end and their channel is closed when ctx is done.
+type PublishedEvents interface {
+	Events(ctx context.Context) []domain.PublishedEvent
+	Subscribe(ctx context.Context) <-chan domain.PublishedEvent
 }
@@ -2,12 +2,44 @@ package interfaces
 
 import (
 	"context"
+	"time"
 	"user-service/internal/core/domain"
 )
 
 type UserRepository interface {
-	GetAll(ctx context.Context) ([]domain.User, error)
+	GetAll(ctx context.Context, query domain.UserQuery) (domain.UserPage, error)
 	Get(ctx context.Context, id string) (domain.User, error)
+	// GetUnscoped is Get including deleted users, whose data is kept until it is erased.
+	GetUnscoped(ctx context.Context, id string) (domain.User, error)
+	Search(ctx context.Context, query string, page domain.SearchPage) (domain.UserSearchResult, error)
 	Save(ctx context.Context, user domain.User) (domain.User, error)
 	Update(ctx context.Context, user domain.User) (domain.User, error)
+	// UpdateUnscoped is Update including deleted users.
+	UpdateUnscoped(ctx context.Context, user domain.User) (domain.User, error)
+	Delete(ctx context.Context, id string) error
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type OutboxRepository interface {
+	Add(ctx context.Context, message domain.OutboxMessage) error
+	// Claim returns up to limit messages that are due at the given time, oldest
+	// first, leaving out messages of users with an older undelivered message.
+	// The messages are claimed until the given time, concurrent callers skip them.
+	Claim(ctx context.Context, at, until time.Time, limit int) ([]domain.OutboxMessage, error)
+	Delete(ctx context.Context, id uint64) error
+	MarkFailed(ctx context.Context, id uint64, reason string, nextAttemptAt time.Time) error
+	// MarkDead stops the delivery of a message, which stays in the outbox.
+	MarkDead(ctx context.Context, id uint64, reason string, at time.Time) error
+	// Scrub replaces the user in every message of the user, dead ones included,
+	// so erased personal data doesn't stay behind in the outbox.
+	Scrub(ctx context.Context, user domain.User) error
+	// PurgeDead deletes the messages given up before the given time and returns
+	// how many were deleted.
+	PurgeDead(ctx context.Context, before time.Time) (int, error)
+	// ListDead returns up to limit dead messages, oldest first.
+	ListDead(ctx context.Context, limit int) ([]domain.OutboxMessage, error)
+	GetDead(ctx context.Context, id uint64) (domain.OutboxMessage, error)
+	// Revive makes a dead message due again at the given time with its attempts reset.
+	Revive(ctx context.Context, id uint64, at time.Time) error
+	DeleteDead(ctx context.Context, id uint64) error
 }
@@ -6,8 +6,12 @@ import (
 )
 
 type UserService interface {
-	GetAll(ctx context.Context) ([]domain.User, error)
+	GetAll(ctx context.Context, query domain.UserQuery) (domain.UserPage, error)
 	Get(ctx context.Context, id string) (domain.User, error)
+	Search(ctx context.Context, query string, page domain.SearchPage) (domain.UserSearchResult, error)
 	Create(ctx context.Context, id, name, lastName, email string) (domain.User, error)
-	UpdateUserDetails(ctx context.Context, id, name, lastName, email string) (domain.User, error)
+	UpdateUserDetails(ctx context.Context, id string, version int64, name, lastName, email string) (domain.User, error)
+	Delete(ctx context.Context, id string) error
+	Erase(ctx context.Context, id, requestedBy string) (domain.User, error)
+	Export(ctx context.Context, id string) (domain.UserExport, error)
 }
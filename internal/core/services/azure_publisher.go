@@ -2,23 +2,28 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"user-service/config"
 	"user-service/internal/core/domain"
 	"user-service/pkg/azure"
+	"user-service/pkg/cloudevents"
+	"user-service/pkg/tracing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type azurePublisher struct {
 	serviceBus *azure.ServiceBus
-	sender     *azservicebus.Sender
+	tracer     trace.Tracer
 	config     *config.Config
 }
 
-func NewAzurePublisher(serviceBus *azure.ServiceBus, cfg *config.Config) *azurePublisher {
-	return &azurePublisher{serviceBus: serviceBus, config: cfg}
+func NewAzurePublisher(serviceBus *azure.ServiceBus, tracerProvider trace.TracerProvider, cfg *config.Config) *azurePublisher {
+	return &azurePublisher{serviceBus: serviceBus, tracer: tracerProvider.Tracer("Azure.Publisher"), config: cfg}
 }
 
 func (rmq *azurePublisher) CreateUser(ctx context.Context, user domain.User) error {
@@ -29,8 +34,16 @@ func (rmq *azurePublisher) UpdateUserDetails(ctx context.Context, user domain.Us
 	return rmq.publishJson(ctx, "update", user)
 }
 
-func (az *azurePublisher) publishJson(ctx context.Context, topic string, body interface{}) error {
-	js, err := json.Marshal(body)
+func (rmq *azurePublisher) DeleteUser(ctx context.Context, user domain.User) error {
+	return rmq.publishJson(ctx, "delete", user)
+}
+
+func (rmq *azurePublisher) EraseUser(ctx context.Context, user domain.User) error {
+	return rmq.publishJson(ctx, "erased", user)
+}
+
+func (az *azurePublisher) publishJson(ctx context.Context, topic string, user domain.User) error {
+	event, err := newUserEvent(az.config, topic, user)
 
 	if err != nil {
 		return err
@@ -38,23 +51,46 @@ func (az *azurePublisher) publishJson(ctx context.Context, topic string, body in
 
 	topic = fmt.Sprintf("user.%s", topic)
 
-	sender, err := az.serviceBus.Client.NewSender(topic, nil)
+	message := &azservicebus.Message{
+		MessageID: &event.ID,
+		Subject:   &topic,
+	}
 
-	defer func(sender *azservicebus.Sender, ctx context.Context) {
-		_ = sender.Close(ctx)
-	}(sender, ctx)
+	if isStructuredMode(az.config) {
+		contentType := cloudevents.StructuredContentType
+		message.ContentType = &contentType
+		message.Body, err = event.Structured()
 
-	if err != nil {
-		return err
+		if err != nil {
+			return err
+		}
+	} else {
+		message.ContentType = &event.DataContentType
+		message.ApplicationProperties = event.Headers()
+		message.Body = event.Data
+	}
+
+	ctx, span := az.tracer.Start(ctx, topic+" send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("servicebus"),
+			semconv.MessagingDestinationKey.String(topic),
+			semconv.MessagingDestinationKindTopic,
+			semconv.MessagingMessageIDKey.String(event.ID),
+			attribute.String("event.type", event.Type)))
+	defer span.End()
+
+	if message.ApplicationProperties == nil {
+		message.ApplicationProperties = map[string]interface{}{}
 	}
 
-	err = sender.SendMessage(ctx, &azservicebus.Message{
-		Body:    js,
-		Subject: &topic,
-	}, nil)
+	tracing.Inject(ctx, message.ApplicationProperties)
+
+	err = az.serviceBus.SendMessage(ctx, topic, message)
 
 	if err != nil {
-		return err
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
 
 	return err
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/pkg/cloudevents"
+	"user-service/pkg/kafka"
+	"user-service/pkg/tracing"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// kafkaContentTypeHeader carries the content type of the record value, as
+// defined by the Kafka protocol binding of CloudEvents.
+const kafkaContentTypeHeader = "content-type"
+
+type kafkaPublisher struct {
+	kafka  *kafka.Kafka
+	tracer trace.Tracer
+	config *config.Config
+}
+
+func NewKafkaPublisher(kafka *kafka.Kafka, tracerProvider trace.TracerProvider, cfg *config.Config) *kafkaPublisher {
+	return &kafkaPublisher{kafka: kafka, tracer: tracerProvider.Tracer("Kafka.Publisher"), config: cfg}
+}
+
+func (k *kafkaPublisher) CreateUser(ctx context.Context, user domain.User) error {
+	return k.publishJson(ctx, "create", user)
+}
+
+func (k *kafkaPublisher) UpdateUserDetails(ctx context.Context, user domain.User) error {
+	return k.publishJson(ctx, "update", user)
+}
+
+func (k *kafkaPublisher) DeleteUser(ctx context.Context, user domain.User) error {
+	return k.publishJson(ctx, "delete", user)
+}
+
+func (k *kafkaPublisher) EraseUser(ctx context.Context, user domain.User) error {
+	return k.publishJson(ctx, "erased", user)
+}
+
+// publishJson produces the event keyed by the user id, so all events of a user
+// go to the same partition and are consumed in order.
+func (k *kafkaPublisher) publishJson(ctx context.Context, topic string, user domain.User) error {
+	event, err := newUserEvent(k.config, topic, user)
+
+	if err != nil {
+		return err
+	}
+
+	topic = fmt.Sprintf("user.%s", topic)
+
+	record := &kgo.Record{
+		Topic:     topic,
+		Key:       []byte(user.ID),
+		Timestamp: event.Time,
+	}
+
+	var headers map[string]interface{}
+
+	if isStructuredMode(k.config) {
+		record.Value, err = event.Structured()
+
+		if err != nil {
+			return err
+		}
+
+		headers = map[string]interface{}{kafkaContentTypeHeader: cloudevents.StructuredContentType}
+	} else {
+		record.Value = event.Data
+		headers = event.KafkaHeaders()
+		headers[kafkaContentTypeHeader] = event.DataContentType
+	}
+
+	ctx, span := k.tracer.Start(ctx, topic+" send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("kafka"),
+			semconv.MessagingDestinationKey.String(topic),
+			semconv.MessagingDestinationKindTopic,
+			semconv.MessagingKafkaMessageKeyKey.String(user.ID),
+			semconv.MessagingMessageIDKey.String(event.ID),
+			attribute.String("event.type", event.Type)))
+	defer span.End()
+
+	tracing.Inject(ctx, headers)
+
+	record.Headers = recordHeaders(headers)
+
+	err = k.kafka.Produce(ctx, record)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// recordHeaders converts the string headers to record headers, sorted by key
+// so records of the same event are identical.
+func recordHeaders(headers map[string]interface{}) []kgo.RecordHeader {
+	keys := make([]string, 0, len(headers))
+
+	for key := range headers {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	result := make([]kgo.RecordHeader, 0, len(keys))
+
+	for _, key := range keys {
+		result = append(result, kgo.RecordHeader{Key: key, Value: []byte(fmt.Sprint(headers[key]))})
+	}
+
+	return result
+}
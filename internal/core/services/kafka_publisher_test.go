@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+	"user-service/pkg/cloudevents"
+	"user-service/pkg/events"
+	"user-service/pkg/kafka"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// KafkaPublisherTestSuite runs against an in-process fake Kafka cluster.
+type KafkaPublisherTestSuite struct {
+	suite.Suite
+	Cluster       *kfake.Cluster
+	TestKafka     *kafka.Kafka
+	TestPublisher interfaces.MessageBusPublisher
+	Cfg           *config.Config
+	TestData      struct {
+		User domain.User
+	}
+}
+
+func (suite *KafkaPublisherTestSuite) SetupSuite() {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(3, "user.create", "user.update", "user.delete", "user.erased"))
+
+	if err != nil {
+		panic(errors.WithStack(err))
+	}
+
+	cfg := &config.Config{}
+	cfg.Kafka.Brokers = cluster.ListenAddrs()
+	cfg.Kafka.ClientID = "user-service-test"
+	cfg.Events.Source = "/bikepack/user-service"
+
+	k, err := kafka.NewKafka(cfg)
+
+	if err != nil {
+		panic(errors.WithStack(err))
+	}
+
+	suite.Cluster = cluster
+	suite.Cfg = cfg
+	suite.TestKafka = k
+	suite.TestPublisher = NewKafkaPublisher(k, trace.NewTracerProvider(), cfg)
+	suite.TestData = struct {
+		User domain.User
+	}{
+		User: domain.User{
+			ID:       "test-id",
+			Name:     "test-name",
+			LastName: "test-lastname",
+		},
+	}
+}
+
+func (suite *KafkaPublisherTestSuite) TearDownSuite() {
+	suite.TestKafka.Close()
+	suite.Cluster.Close()
+}
+
+func (suite *KafkaPublisherTestSuite) SetupTest() {
+	suite.Cfg.Events.Mode = ""
+}
+
+// consume reads n records of the topic from the beginning.
+func (suite *KafkaPublisherTestSuite) consume(topic string, n int) []*kgo.Record {
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(suite.Cluster.ListenAddrs()...),
+		kgo.ConsumeTopics(topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))
+
+	suite.Require().NoError(err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var records []*kgo.Record
+
+	for len(records) < n {
+		fetches := client.PollFetches(ctx)
+
+		suite.Require().NoError(ctx.Err())
+
+		records = append(records, fetches.Records()...)
+	}
+
+	return records
+}
+
+func header(record *kgo.Record, key string) string {
+	for _, h := range record.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+
+	return ""
+}
+
+func (suite *KafkaPublisherTestSuite) TestKafkaPublisher_CreateUser() {
+	ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+
+	err := suite.TestPublisher.CreateUser(ctx, suite.TestData.User)
+
+	suite.NoError(err)
+
+	records := suite.consume("user.create", 1)
+	record := records[0]
+
+	suite.Equal(suite.TestData.User.ID, string(record.Key))
+	suite.Equal(cloudevents.JSONContentType, header(record, "content-type"))
+	suite.Equal("bikepack.user.created", header(record, "ce_type"))
+	suite.Equal(suite.TestData.User.ID, header(record, "ce_subject"))
+	suite.NotEmpty(header(record, "ce_id"))
+	suite.Contains(header(record, "traceparent"), span.SpanContext().TraceID().String())
+
+	var user events.UserV1
+
+	err = json.Unmarshal(record.Value, &user)
+	suite.NoError(err)
+
+	suite.Equal(events.NewUserV1(suite.TestData.User), user)
+}
+
+func (suite *KafkaPublisherTestSuite) TestKafkaPublisher_Structured() {
+	suite.Cfg.Events.Mode = cloudevents.StructuredMode
+
+	err := suite.TestPublisher.DeleteUser(context.Background(), suite.TestData.User)
+
+	suite.NoError(err)
+
+	record := suite.consume("user.delete", 1)[0]
+
+	suite.Equal(cloudevents.StructuredContentType, header(record, "content-type"))
+	suite.Empty(header(record, "ce_type"))
+
+	var event cloudevents.Event
+
+	err = json.Unmarshal(record.Value, &event)
+	suite.NoError(err)
+
+	suite.Equal("bikepack.user.deleted", event.Type)
+	suite.Equal(suite.TestData.User.ID, event.Subject)
+}
+
+func (suite *KafkaPublisherTestSuite) TestKafkaPublisher_OrderedPerUser() {
+	users := []domain.User{
+		{ID: "first-id", Name: "first-1"},
+		{ID: "second-id", Name: "second-1"},
+		{ID: "first-id", Name: "first-2"},
+		{ID: "second-id", Name: "second-2"},
+		{ID: "first-id", Name: "first-3"},
+	}
+
+	for _, user := range users {
+		suite.Require().NoError(suite.TestPublisher.UpdateUserDetails(context.Background(), user))
+	}
+
+	records := suite.consume("user.update", len(users))
+
+	partitions := map[string]int32{}
+	names := map[string][]string{}
+
+	for _, record := range records {
+		key := string(record.Key)
+
+		if partition, ok := partitions[key]; ok {
+			suite.Equal(partition, record.Partition, "events of a user must share a partition")
+		}
+
+		partitions[key] = record.Partition
+
+		var user events.UserV1
+
+		suite.Require().NoError(json.Unmarshal(record.Value, &user))
+
+		names[key] = append(names[key], user.Name)
+	}
+
+	suite.Equal([]string{"first-1", "first-2", "first-3"}, names["first-id"])
+	suite.Equal([]string{"second-1", "second-2"}, names["second-id"])
+}
+
+func (suite *KafkaPublisherTestSuite) TestKafkaPublisher_Closed() {
+	k, err := kafka.NewKafka(suite.Cfg)
+
+	suite.Require().NoError(err)
+
+	k.Close()
+
+	err = NewKafkaPublisher(k, trace.NewTracerProvider(), suite.Cfg).CreateUser(context.Background(), suite.TestData.User)
+
+	suite.ErrorIs(err, kafka.ErrClosed)
+}
+
+func TestUnit_KafkaPublisherTestSuite(t *testing.T) {
+	suite.Run(t, new(KafkaPublisherTestSuite))
+}
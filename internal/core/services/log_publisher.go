@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/pkg/logging"
+)
+
+// logPublisher writes every event as a structured log line instead of sending
+// it, so the service runs without a message bus during local development.
+type logPublisher struct {
+	logger logging.Logger
+	config *config.Config
+}
+
+func NewLogPublisher(logger logging.Logger, cfg *config.Config) *logPublisher {
+	return &logPublisher{logger: logger, config: cfg}
+}
+
+func (l *logPublisher) CreateUser(ctx context.Context, user domain.User) error {
+	return l.publishJson(ctx, "create", user)
+}
+
+func (l *logPublisher) UpdateUserDetails(ctx context.Context, user domain.User) error {
+	return l.publishJson(ctx, "update", user)
+}
+
+func (l *logPublisher) DeleteUser(ctx context.Context, user domain.User) error {
+	return l.publishJson(ctx, "delete", user)
+}
+
+func (l *logPublisher) EraseUser(ctx context.Context, user domain.User) error {
+	return l.publishJson(ctx, "erased", user)
+}
+
+func (l *logPublisher) publishJson(ctx context.Context, topic string, user domain.User) error {
+	event, err := newUserEvent(l.config, topic, user)
+
+	if err != nil {
+		return err
+	}
+
+	l.logger.Info(ctx, "published user event",
+		"topic", fmt.Sprintf("user.%s", topic),
+		"id", event.ID,
+		"type", event.Type,
+		"subject", event.Subject,
+		"data", string(event.Data))
+
+	return nil
+}
+
+// noopPublisher discards every event, for deployments without a message bus.
+type noopPublisher struct{}
+
+func NewNoopPublisher() *noopPublisher {
+	return &noopPublisher{}
+}
+
+func (noopPublisher) CreateUser(ctx context.Context, user domain.User) error {
+	return nil
+}
+
+func (noopPublisher) UpdateUserDetails(ctx context.Context, user domain.User) error {
+	return nil
+}
+
+func (noopPublisher) DeleteUser(ctx context.Context, user domain.User) error {
+	return nil
+}
+
+func (noopPublisher) EraseUser(ctx context.Context, user domain.User) error {
+	return nil
+}
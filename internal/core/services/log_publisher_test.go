@@ -0,0 +1,46 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/pkg/logging"
+)
+
+type LogPublisherTestSuite struct {
+	suite.Suite
+	Output *bytes.Buffer
+	Logger *logging.SimpleLogger
+	Cfg    *config.Config
+}
+
+func (suite *LogPublisherTestSuite) SetupTest() {
+	suite.Output = &bytes.Buffer{}
+	suite.Logger = &logging.SimpleLogger{Out: suite.Output}
+	suite.Cfg = &config.Config{}
+}
+
+func (suite *LogPublisherTestSuite) TestLogPublisher_CreateUser() {
+	err := NewLogPublisher(suite.Logger, suite.Cfg).CreateUser(context.Background(), domain.User{ID: "test-id", Name: "test-name"})
+
+	suite.NoError(err)
+
+	line := suite.Output.String()
+
+	suite.Equal(1, strings.Count(line, "\n"))
+	suite.True(strings.HasPrefix(line, "INFO: published user event topic=user.create id="))
+	suite.Contains(line, " type=bikepack.user.created subject=test-id data=\"{")
+	suite.Contains(line, `\"name\":\"test-name\"`)
+}
+
+func (suite *LogPublisherTestSuite) TestNoopPublisher() {
+	suite.NoError(NewNoopPublisher().EraseUser(context.Background(), domain.User{ID: "test-id"}))
+}
+
+func TestUnit_LogPublisherTestSuite(t *testing.T) {
+	suite.Run(t, new(LogPublisherTestSuite))
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"user-service/config"
+	"user-service/internal/core/domain"
+)
+
+const (
+	// memoryPublisherCapacity is the number of most recent events kept.
+	memoryPublisherCapacity = 1000
+
+	memorySubscriberBuffer = 100
+)
+
+// memoryPublisher keeps the most recent events in memory and hands them to
+// subscribers, so the service and its tests run without a message bus.
+// Subscribers that fall more than their buffer behind miss events. It is safe
+// for concurrent use.
+type memoryPublisher struct {
+	config      *config.Config
+	mutex       sync.Mutex
+	events      []domain.PublishedEvent
+	subscribers map[chan domain.PublishedEvent]struct{}
+}
+
+func NewMemoryPublisher(cfg *config.Config) *memoryPublisher {
+	return &memoryPublisher{config: cfg, subscribers: make(map[chan domain.PublishedEvent]struct{})}
+}
+
+func (m *memoryPublisher) CreateUser(ctx context.Context, user domain.User) error {
+	return m.publishJson(ctx, "create", user)
+}
+
+func (m *memoryPublisher) UpdateUserDetails(ctx context.Context, user domain.User) error {
+	return m.publishJson(ctx, "update", user)
+}
+
+func (m *memoryPublisher) DeleteUser(ctx context.Context, user domain.User) error {
+	return m.publishJson(ctx, "delete", user)
+}
+
+func (m *memoryPublisher) EraseUser(ctx context.Context, user domain.User) error {
+	return m.publishJson(ctx, "erased", user)
+}
+
+// Events returns the kept events, oldest first.
+func (m *memoryPublisher) Events(ctx context.Context) []domain.PublishedEvent {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	events := make([]domain.PublishedEvent, len(m.events))
+	copy(events, m.events)
+
+	return events
+}
+
+// Subscribe returns a channel receiving the events published from now on,
+// until ctx is done.
+func (m *memoryPublisher) Subscribe(ctx context.Context) <-chan domain.PublishedEvent {
+	subscriber := make(chan domain.PublishedEvent, memorySubscriberBuffer)
+
+	m.mutex.Lock()
+	m.subscribers[subscriber] = struct{}{}
+	m.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		delete(m.subscribers, subscriber)
+		close(subscriber)
+	}()
+
+	return subscriber
+}
+
+func (m *memoryPublisher) publishJson(ctx context.Context, topic string, user domain.User) error {
+	event, err := newUserEvent(m.config, topic, user)
+
+	if err != nil {
+		return err
+	}
+
+	published := domain.PublishedEvent{
+		Topic:   fmt.Sprintf("user.%s", topic),
+		ID:      event.ID,
+		Type:    event.Type,
+		Subject: event.Subject,
+		Time:    event.Time,
+		Data:    event.Data,
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.events = append(m.events, published)
+
+	if len(m.events) > memoryPublisherCapacity {
+		m.events = m.events[len(m.events)-memoryPublisherCapacity:]
+	}
+
+	for subscriber := range m.subscribers {
+		select {
+		case subscriber <- published:
+		default:
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain"
+)
+
+type MemoryPublisherTestSuite struct {
+	suite.Suite
+	TestPublisher *memoryPublisher
+}
+
+func (suite *MemoryPublisherTestSuite) SetupTest() {
+	suite.TestPublisher = NewMemoryPublisher(&config.Config{})
+}
+
+func (suite *MemoryPublisherTestSuite) TestMemoryPublisher_Events() {
+	suite.NoError(suite.TestPublisher.CreateUser(context.Background(), domain.User{ID: "test-id", Name: "test-name"}))
+	suite.NoError(suite.TestPublisher.DeleteUser(context.Background(), domain.User{ID: "test-id"}))
+
+	events := suite.TestPublisher.Events(context.Background())
+
+	suite.Require().Len(events, 2)
+	suite.Equal("user.create", events[0].Topic)
+	suite.Equal("bikepack.user.created", events[0].Type)
+	suite.Equal("test-id", events[0].Subject)
+	suite.Contains(string(events[0].Data), `"name":"test-name"`)
+	suite.Equal("user.delete", events[1].Topic)
+}
+
+func (suite *MemoryPublisherTestSuite) TestMemoryPublisher_Capacity() {
+	for i := 0; i < memoryPublisherCapacity+5; i++ {
+		suite.NoError(suite.TestPublisher.UpdateUserDetails(context.Background(), domain.User{ID: "test-id", Version: int64(i)}))
+	}
+
+	events := suite.TestPublisher.Events(context.Background())
+
+	suite.Len(events, memoryPublisherCapacity)
+}
+
+func (suite *MemoryPublisherTestSuite) TestMemoryPublisher_Subscribe() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	subscription := suite.TestPublisher.Subscribe(ctx)
+
+	suite.NoError(suite.TestPublisher.EraseUser(context.Background(), domain.User{ID: "test-id"}))
+
+	select {
+	case event := <-subscription:
+		suite.Equal("user.erased", event.Topic)
+	case <-time.After(time.Second):
+		suite.Fail("no event received")
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-subscription:
+		suite.False(ok)
+	case <-time.After(time.Second):
+		suite.Fail("subscription not closed")
+	}
+}
+
+func (suite *MemoryPublisherTestSuite) TestMemoryPublisher_Concurrent() {
+	var wg sync.WaitGroup
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	suite.TestPublisher.Subscribe(ctx)
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			_ = suite.TestPublisher.CreateUser(context.Background(), domain.User{ID: "test-id"})
+		}()
+	}
+
+	wg.Wait()
+
+	suite.Len(suite.TestPublisher.Events(context.Background()), 50)
+}
+
+func TestUnit_MemoryPublisherTestSuite(t *testing.T) {
+	suite.Run(t, new(MemoryPublisherTestSuite))
+}
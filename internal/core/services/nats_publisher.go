@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/pkg/cloudevents"
+	"user-service/pkg/jetstream"
+	"user-service/pkg/tracing"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// natsContentTypeHeader carries the content type of the message data, as
+// defined by the NATS protocol binding of CloudEvents.
+const natsContentTypeHeader = "Content-Type"
+
+type natsPublisher struct {
+	jetStream *jetstream.JetStream
+	tracer    trace.Tracer
+	config    *config.Config
+}
+
+func NewNATSPublisher(jetStream *jetstream.JetStream, tracerProvider trace.TracerProvider, cfg *config.Config) *natsPublisher {
+	return &natsPublisher{jetStream: jetStream, tracer: tracerProvider.Tracer("NATS.Publisher"), config: cfg}
+}
+
+func (n *natsPublisher) CreateUser(ctx context.Context, user domain.User) error {
+	return n.publishJson(ctx, "create", user)
+}
+
+func (n *natsPublisher) UpdateUserDetails(ctx context.Context, user domain.User) error {
+	return n.publishJson(ctx, "update", user)
+}
+
+func (n *natsPublisher) DeleteUser(ctx context.Context, user domain.User) error {
+	return n.publishJson(ctx, "delete", user)
+}
+
+func (n *natsPublisher) EraseUser(ctx context.Context, user domain.User) error {
+	return n.publishJson(ctx, "erased", user)
+}
+
+// publishJson sets the event id as the JetStream message id, so a message that
+// is relayed again within the duplicate window is stored only once.
+func (n *natsPublisher) publishJson(ctx context.Context, topic string, user domain.User) error {
+	event, err := newUserEvent(n.config, topic, user)
+
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("user.%s", topic)
+	msg := nats.NewMsg(subject)
+
+	var headers map[string]interface{}
+
+	if isStructuredMode(n.config) {
+		msg.Data, err = event.Structured()
+
+		if err != nil {
+			return err
+		}
+
+		headers = map[string]interface{}{natsContentTypeHeader: cloudevents.StructuredContentType}
+	} else {
+		msg.Data = event.Data
+		headers = event.NATSHeaders()
+		headers[natsContentTypeHeader] = event.DataContentType
+	}
+
+	ctx, span := n.tracer.Start(ctx, subject+" send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("nats"),
+			semconv.MessagingDestinationKey.String(subject),
+			semconv.MessagingDestinationKindTopic,
+			semconv.MessagingMessageIDKey.String(event.ID),
+			attribute.String("event.type", event.Type)))
+	defer span.End()
+
+	tracing.Inject(ctx, headers)
+
+	for key, value := range headers {
+		msg.Header.Set(key, fmt.Sprint(value))
+	}
+
+	msg.Header.Set(nats.MsgIdHdr, event.ID)
+
+	err = n.jetStream.Publish(ctx, msg)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
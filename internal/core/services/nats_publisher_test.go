@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"os"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+	"user-service/pkg/cloudevents"
+	"user-service/pkg/events"
+	"user-service/pkg/jetstream"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisherTestSuite runs against an embedded NATS server with JetStream.
+type NATSPublisherTestSuite struct {
+	suite.Suite
+	Server        *server.Server
+	StoreDir      string
+	TestJetStream *jetstream.JetStream
+	TestPublisher interfaces.MessageBusPublisher
+	Cfg           *config.Config
+	TestData      struct {
+		User domain.User
+	}
+}
+
+func (suite *NATSPublisherTestSuite) SetupSuite() {
+	storeDir, err := os.MkdirTemp("", "user-service-jetstream")
+
+	if err != nil {
+		panic(errors.WithStack(err))
+	}
+
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, JetStream: true, StoreDir: storeDir})
+
+	if err != nil {
+		panic(errors.WithStack(err))
+	}
+
+	go srv.Start()
+
+	if !srv.ReadyForConnections(10 * time.Second) {
+		panic("nats server not ready")
+	}
+
+	cfg := &config.Config{}
+	cfg.Server.Service = "user-service-test"
+	cfg.NATS.URL = srv.ClientURL()
+	cfg.Events.Source = "/bikepack/user-service"
+
+	js, err := jetstream.NewJetStream(cfg)
+
+	if err != nil {
+		panic(errors.WithStack(err))
+	}
+
+	suite.Server = srv
+	suite.StoreDir = storeDir
+	suite.Cfg = cfg
+	suite.TestJetStream = js
+	suite.TestPublisher = NewNATSPublisher(js, trace.NewTracerProvider(), cfg)
+	suite.TestData = struct {
+		User domain.User
+	}{
+		User: domain.User{
+			ID:       "test-id",
+			Name:     "test-name",
+			LastName: "test-lastname",
+		},
+	}
+}
+
+func (suite *NATSPublisherTestSuite) TearDownSuite() {
+	suite.TestJetStream.Close()
+	suite.Server.Shutdown()
+	_ = os.RemoveAll(suite.StoreDir)
+}
+
+func (suite *NATSPublisherTestSuite) SetupTest() {
+	suite.Cfg.Events.Mode = ""
+	suite.Require().NoError(suite.TestJetStream.Context().PurgeStream("USERS"))
+}
+
+func (suite *NATSPublisherTestSuite) lastMessage(subject string) *nats.RawStreamMsg {
+	info, err := suite.TestJetStream.Context().StreamInfo("USERS")
+
+	suite.Require().NoError(err)
+
+	msg, err := suite.TestJetStream.Context().GetMsg("USERS", info.State.LastSeq)
+
+	suite.Require().NoError(err)
+	suite.Equal(subject, msg.Subject)
+
+	return msg
+}
+
+func (suite *NATSPublisherTestSuite) TestNATSPublisher_CreateUser() {
+	ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+
+	err := suite.TestPublisher.CreateUser(ctx, suite.TestData.User)
+
+	suite.NoError(err)
+
+	msg := suite.lastMessage("user.create")
+
+	suite.Equal(cloudevents.JSONContentType, msg.Header.Get("Content-Type"))
+	suite.Equal("bikepack.user.created", msg.Header.Get("ce-type"))
+	suite.Equal(suite.TestData.User.ID, msg.Header.Get("ce-subject"))
+	suite.Equal(msg.Header.Get("ce-id"), msg.Header.Get(nats.MsgIdHdr))
+	suite.Contains(msg.Header.Get("traceparent"), span.SpanContext().TraceID().String())
+
+	var user events.UserV1
+
+	err = json.Unmarshal(msg.Data, &user)
+	suite.NoError(err)
+
+	suite.Equal(events.NewUserV1(suite.TestData.User), user)
+}
+
+func (suite *NATSPublisherTestSuite) TestNATSPublisher_UpdateUserDetails_Structured() {
+	suite.Cfg.Events.Mode = cloudevents.StructuredMode
+
+	err := suite.TestPublisher.UpdateUserDetails(context.Background(), suite.TestData.User)
+
+	suite.NoError(err)
+
+	msg := suite.lastMessage("user.update")
+
+	suite.Equal(cloudevents.StructuredContentType, msg.Header.Get("Content-Type"))
+
+	var event cloudevents.Event
+
+	err = json.Unmarshal(msg.Data, &event)
+	suite.NoError(err)
+
+	suite.Equal("bikepack.user.updated", event.Type)
+	suite.Equal(msg.Header.Get(nats.MsgIdHdr), event.ID)
+}
+
+func (suite *NATSPublisherTestSuite) TestNATSPublisher_Deduplicates() {
+	// Purging the stream does not reset the duplicate window, so this test
+	// uses a user that no other test publishes.
+	user := domain.User{ID: "duplicate-id", Version: 1}
+
+	suite.NoError(suite.TestPublisher.CreateUser(context.Background(), user))
+	suite.NoError(suite.TestPublisher.CreateUser(context.Background(), user))
+
+	next := user
+	next.Version++
+
+	suite.NoError(suite.TestPublisher.CreateUser(context.Background(), next))
+
+	info, err := suite.TestJetStream.Context().StreamInfo("USERS")
+
+	suite.NoError(err)
+	suite.EqualValues(2, info.State.Msgs)
+}
+
+func TestUnit_NATSPublisherTestSuite(t *testing.T) {
+	suite.Run(t, new(NATSPublisherTestSuite))
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+)
+
+// outboxDeadLetters is the dead letter queue of the outbox: the published
+// messages the relay gave up on. Replaying a message makes it due again, so the
+// relay publishes it with the next batch.
+type outboxDeadLetters struct {
+	outbox interfaces.OutboxRepository
+}
+
+func NewOutboxDeadLetters(outbox interfaces.OutboxRepository) *outboxDeadLetters {
+	return &outboxDeadLetters{outbox: outbox}
+}
+
+func (deadLetters *outboxDeadLetters) List(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	messages, err := deadLetters.outbox.ListDead(ctx, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.DeadLetter, 0, len(messages))
+
+	for _, message := range messages {
+		result = append(result, newDeadLetterFromOutboxMessage(message))
+	}
+
+	return result, nil
+}
+
+func (deadLetters *outboxDeadLetters) Get(ctx context.Context, id string) (domain.DeadLetter, error) {
+	messageID, err := parseOutboxMessageID(id)
+
+	if err != nil {
+		return domain.DeadLetter{}, err
+	}
+
+	message, err := deadLetters.outbox.GetDead(ctx, messageID)
+
+	if err != nil {
+		return domain.DeadLetter{}, translateOutboxDeadLetterError(err, id)
+	}
+
+	return newDeadLetterFromOutboxMessage(message), nil
+}
+
+func (deadLetters *outboxDeadLetters) Replay(ctx context.Context, id string) error {
+	messageID, err := parseOutboxMessageID(id)
+
+	if err != nil {
+		return err
+	}
+
+	return translateOutboxDeadLetterError(deadLetters.outbox.Revive(ctx, messageID, time.Now().UTC()), id)
+}
+
+func (deadLetters *outboxDeadLetters) Delete(ctx context.Context, id string) error {
+	messageID, err := parseOutboxMessageID(id)
+
+	if err != nil {
+		return err
+	}
+
+	return translateOutboxDeadLetterError(deadLetters.outbox.DeleteDead(ctx, messageID), id)
+}
+
+func (deadLetters *outboxDeadLetters) Purge(ctx context.Context) (int, error) {
+	return deadLetters.outbox.PurgeDead(ctx, time.Now().UTC())
+}
+
+func newDeadLetterFromOutboxMessage(message domain.OutboxMessage) domain.DeadLetter {
+	return domain.DeadLetter{
+		ID:          strconv.FormatUint(message.ID, 10),
+		Type:        message.Type,
+		Reason:      message.LastError,
+		Attempts:    message.Attempts,
+		FailedAt:    message.DeadAt,
+		ContentType: "application/json",
+		Body:        message.Payload,
+	}
+}
+
+// parseOutboxMessageID treats ids that are not outbox message ids as unknown.
+func parseOutboxMessageID(id string) (uint64, error) {
+	messageID, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		return 0, domain.NewNotFoundError("dead letter", id)
+	}
+
+	return messageID, nil
+}
+
+func translateOutboxDeadLetterError(err error, id string) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewNotFoundError("dead letter", id)
+	}
+
+	return err
+}
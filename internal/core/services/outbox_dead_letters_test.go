@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	mock2 "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+	"user-service/internal/core/domain"
+	"user-service/internal/mock"
+)
+
+type OutboxDeadLettersTestSuite struct {
+	suite.Suite
+	MockOutbox      *mock.OutboxRepository
+	TestDeadLetters *outboxDeadLetters
+	TestData        struct {
+		Message domain.OutboxMessage
+	}
+}
+
+func (suite *OutboxDeadLettersTestSuite) SetupSuite() {
+	outbox := new(mock.OutboxRepository)
+
+	message, _ := domain.NewOutboxMessage(domain.UserCreatedEvent, domain.User{ID: "test-id"}, time.Now().UTC())
+	deadAt := time.Now().UTC()
+
+	message.ID = 5
+	message.Attempts = 25
+	message.LastError = "broker unavailable"
+	message.DeadAt = &deadAt
+
+	suite.MockOutbox = outbox
+	suite.TestDeadLetters = NewOutboxDeadLetters(outbox)
+	suite.TestData = struct {
+		Message domain.OutboxMessage
+	}{
+		Message: message,
+	}
+}
+
+func (suite *OutboxDeadLettersTestSuite) SetupTest() {
+	suite.MockOutbox.ExpectedCalls = nil
+	suite.MockOutbox.Calls = nil
+}
+
+func (suite *OutboxDeadLettersTestSuite) TestOutboxDeadLetters_List() {
+	suite.MockOutbox.On("ListDead", 20).Return([]domain.OutboxMessage{suite.TestData.Message}, nil)
+
+	result, err := suite.TestDeadLetters.List(context.Background(), 20)
+
+	suite.NoError(err)
+	suite.Require().Len(result, 1)
+	suite.Equal("5", result[0].ID)
+	suite.Equal(domain.UserCreatedEvent, result[0].Type)
+	suite.Equal("broker unavailable", result[0].Reason)
+	suite.Equal(25, result[0].Attempts)
+	suite.Equal(suite.TestData.Message.DeadAt, result[0].FailedAt)
+	suite.Equal("application/json", result[0].ContentType)
+	suite.Equal(suite.TestData.Message.Payload, result[0].Body)
+}
+
+func (suite *OutboxDeadLettersTestSuite) TestOutboxDeadLetters_Get_NotFound() {
+	suite.MockOutbox.On("GetDead", uint64(6)).Return(domain.OutboxMessage{}, domain.NewNotFoundError("outbox message", "6"))
+
+	_, err := suite.TestDeadLetters.Get(context.Background(), "6")
+
+	var notFoundErr *domain.NotFoundError
+	suite.Require().ErrorAs(err, &notFoundErr)
+	suite.Equal("dead letter", notFoundErr.Resource)
+}
+
+func (suite *OutboxDeadLettersTestSuite) TestOutboxDeadLetters_Get_InvalidID() {
+	_, err := suite.TestDeadLetters.Get(context.Background(), "message-id")
+
+	suite.ErrorIs(err, domain.ErrNotFound)
+	suite.MockOutbox.AssertNotCalled(suite.T(), "GetDead", mock2.Anything)
+}
+
+func (suite *OutboxDeadLettersTestSuite) TestOutboxDeadLetters_Replay() {
+	suite.MockOutbox.On("Revive", uint64(5), mock2.Anything).Return(nil)
+
+	err := suite.TestDeadLetters.Replay(context.Background(), "5")
+
+	suite.NoError(err)
+
+	at := suite.MockOutbox.Calls[0].Arguments.Get(1).(time.Time)
+	suite.WithinDuration(time.Now(), at, time.Second)
+}
+
+func (suite *OutboxDeadLettersTestSuite) TestOutboxDeadLetters_Delete_Failed() {
+	suite.MockOutbox.On("DeleteDead", uint64(5)).Return(errors.New("database unavailable"))
+
+	err := suite.TestDeadLetters.Delete(context.Background(), "5")
+
+	suite.EqualError(err, "database unavailable")
+}
+
+func (suite *OutboxDeadLettersTestSuite) TestOutboxDeadLetters_Purge() {
+	suite.MockOutbox.On("PurgeDead", mock2.Anything).Return(3, nil)
+
+	purged, err := suite.TestDeadLetters.Purge(context.Background())
+
+	suite.NoError(err)
+	suite.Equal(3, purged)
+}
+
+func TestUnit_OutboxDeadLettersTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxDeadLettersTestSuite))
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+	"user-service/pkg/tracing"
+)
+
+// outboxPublisher stores events in the outbox instead of sending them, so they
+// are committed in the same transaction as the user change that caused them.
+type outboxPublisher struct {
+	outbox interfaces.OutboxRepository
+}
+
+func NewOutboxPublisher(outbox interfaces.OutboxRepository) *outboxPublisher {
+	return &outboxPublisher{outbox: outbox}
+}
+
+func (pub *outboxPublisher) CreateUser(ctx context.Context, user domain.User) error {
+	return pub.add(ctx, domain.UserCreatedEvent, user)
+}
+
+func (pub *outboxPublisher) UpdateUserDetails(ctx context.Context, user domain.User) error {
+	return pub.add(ctx, domain.UserUpdatedEvent, user)
+}
+
+func (pub *outboxPublisher) DeleteUser(ctx context.Context, user domain.User) error {
+	return pub.add(ctx, domain.UserDeletedEvent, user)
+}
+
+// EraseUser also erases the user from its earlier messages, so the erased data
+// doesn't stay behind in messages that are still waiting or were given up.
+func (pub *outboxPublisher) EraseUser(ctx context.Context, user domain.User) error {
+	if err := pub.outbox.Scrub(ctx, user); err != nil {
+		return err
+	}
+
+	return pub.add(ctx, domain.UserErasedEvent, user)
+}
+
+func (pub *outboxPublisher) add(ctx context.Context, eventType string, user domain.User) error {
+	message, err := domain.NewOutboxMessage(eventType, user, time.Now().UTC())
+
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]interface{}{}
+	tracing.Inject(ctx, headers)
+
+	message.TraceParent, _ = headers["traceparent"].(string)
+	message.TraceState, _ = headers["tracestate"].(string)
+
+	return pub.outbox.Add(ctx, message)
+}
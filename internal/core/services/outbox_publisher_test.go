@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	mock2 "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+	"user-service/internal/mock"
+)
+
+type OutboxPublisherTestSuite struct {
+	suite.Suite
+	MockOutbox    *mock.OutboxRepository
+	TestPublisher interfaces.MessageBusPublisher
+	TestData      struct {
+		User domain.User
+	}
+}
+
+func (suite *OutboxPublisherTestSuite) SetupSuite() {
+	outbox := new(mock.OutboxRepository)
+
+	suite.MockOutbox = outbox
+	suite.TestPublisher = NewOutboxPublisher(outbox)
+	suite.TestData = struct {
+		User domain.User
+	}{
+		User: domain.User{
+			ID:       "test-id",
+			Name:     "test-name",
+			LastName: "test-lastname",
+			Email:    "test@email.com",
+			Version:  1,
+		},
+	}
+}
+
+func (suite *OutboxPublisherTestSuite) SetupTest() {
+	suite.MockOutbox.ExpectedCalls = nil
+	suite.MockOutbox.Calls = nil
+}
+
+func (suite *OutboxPublisherTestSuite) messageOf(eventType string) interface{} {
+	return mock2.MatchedBy(func(message domain.OutboxMessage) bool {
+		user, err := message.User()
+
+		return err == nil && message.Type == eventType && message.AggregateID == suite.TestData.User.ID && user == suite.TestData.User
+	})
+}
+
+func (suite *OutboxPublisherTestSuite) TestOutboxPublisher_CreateUser() {
+	suite.MockOutbox.On("Add", suite.messageOf(domain.UserCreatedEvent)).Return(nil)
+
+	err := suite.TestPublisher.CreateUser(context.Background(), suite.TestData.User)
+
+	suite.NoError(err)
+	suite.MockOutbox.AssertNumberOfCalls(suite.T(), "Add", 1)
+}
+
+func (suite *OutboxPublisherTestSuite) TestOutboxPublisher_UpdateUserDetails() {
+	suite.MockOutbox.On("Add", suite.messageOf(domain.UserUpdatedEvent)).Return(nil)
+
+	err := suite.TestPublisher.UpdateUserDetails(context.Background(), suite.TestData.User)
+
+	suite.NoError(err)
+	suite.MockOutbox.AssertNumberOfCalls(suite.T(), "Add", 1)
+}
+
+func (suite *OutboxPublisherTestSuite) TestOutboxPublisher_DeleteUser() {
+	suite.MockOutbox.On("Add", suite.messageOf(domain.UserDeletedEvent)).Return(nil)
+
+	err := suite.TestPublisher.DeleteUser(context.Background(), suite.TestData.User)
+
+	suite.NoError(err)
+	suite.MockOutbox.AssertNumberOfCalls(suite.T(), "Add", 1)
+}
+
+func (suite *OutboxPublisherTestSuite) TestOutboxPublisher_EraseUser() {
+	suite.MockOutbox.On("Scrub", suite.TestData.User).Return(nil)
+	suite.MockOutbox.On("Add", suite.messageOf(domain.UserErasedEvent)).Return(nil)
+
+	err := suite.TestPublisher.EraseUser(context.Background(), suite.TestData.User)
+
+	suite.NoError(err)
+	suite.MockOutbox.AssertNumberOfCalls(suite.T(), "Scrub", 1)
+	suite.MockOutbox.AssertNumberOfCalls(suite.T(), "Add", 1)
+}
+
+func (suite *OutboxPublisherTestSuite) TestOutboxPublisher_EraseUser_ScrubFailed() {
+	suite.MockOutbox.On("Scrub", suite.TestData.User).Return(errors.New("database unavailable"))
+
+	err := suite.TestPublisher.EraseUser(context.Background(), suite.TestData.User)
+
+	suite.Error(err)
+	suite.MockOutbox.AssertNotCalled(suite.T(), "Add", mock2.Anything)
+}
+
+func (suite *OutboxPublisherTestSuite) TestOutboxPublisher_TraceContext() {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	suite.MockOutbox.On("Add", mock2.MatchedBy(func(message domain.OutboxMessage) bool {
+		return message.TraceParent == "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	})).Return(nil)
+
+	err := suite.TestPublisher.CreateUser(ctx, suite.TestData.User)
+
+	suite.NoError(err)
+	suite.MockOutbox.AssertNumberOfCalls(suite.T(), "Add", 1)
+}
+
+func (suite *OutboxPublisherTestSuite) TestOutboxPublisher_AddFailed() {
+	suite.MockOutbox.On("Add", mock2.Anything).Return(errors.New("could not write outbox"))
+
+	err := suite.TestPublisher.CreateUser(context.Background(), suite.TestData.User)
+
+	suite.Error(err)
+}
+
+func TestUnit_OutboxPublisherTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxPublisherTestSuite))
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+	"user-service/pkg/logging"
+	"user-service/pkg/tracing"
+)
+
+const (
+	defaultOutboxPollInterval    = time.Second
+	defaultOutboxBatchSize       = 100
+	defaultOutboxRetryBackoff    = time.Second
+	defaultOutboxMaxRetryBackoff = 5 * time.Minute
+	defaultOutboxMaxAttempts     = 25
+	defaultOutboxClaimTimeout    = time.Minute
+	defaultOutboxDeadRetention   = 7 * 24 * time.Hour
+	outboxPurgeInterval          = time.Hour
+)
+
+// errUndeliverable marks messages that fail on every attempt, so they are not retried.
+var errUndeliverable = errors.New("outbox message is undeliverable")
+
+// outboxRelay drains the outbox to the message bus. A message is only removed
+// after the publisher accepted it, so delivery is at-least-once. Messages of a
+// user are sent in the order they were written: once one of them fails, the
+// later ones wait until it has been delivered or is given up after MaxAttempts.
+// Every batch is claimed for ClaimTimeout before it is published, so several
+// instances can run the relay side by side. No transaction is open while
+// messages are published. Given up messages are deleted after DeadRetention.
+type outboxRelay struct {
+	outbox    interfaces.OutboxRepository
+	publisher interfaces.MessageBusPublisher
+	logger    logging.Logger
+	config    config.Outbox
+}
+
+func NewOutboxRelay(outbox interfaces.OutboxRepository, publisher interfaces.MessageBusPublisher, logger logging.Logger, cfg *config.Config) *outboxRelay {
+	relayConfig := cfg.Outbox
+
+	if relayConfig.PollInterval <= 0 {
+		relayConfig.PollInterval = defaultOutboxPollInterval
+	}
+
+	if relayConfig.BatchSize <= 0 {
+		relayConfig.BatchSize = defaultOutboxBatchSize
+	}
+
+	if relayConfig.RetryBackoff <= 0 {
+		relayConfig.RetryBackoff = defaultOutboxRetryBackoff
+	}
+
+	if relayConfig.MaxRetryBackoff < relayConfig.RetryBackoff {
+		relayConfig.MaxRetryBackoff = defaultOutboxMaxRetryBackoff
+	}
+
+	if relayConfig.MaxAttempts <= 0 {
+		relayConfig.MaxAttempts = defaultOutboxMaxAttempts
+	}
+
+	if relayConfig.ClaimTimeout <= 0 {
+		relayConfig.ClaimTimeout = defaultOutboxClaimTimeout
+	}
+
+	if relayConfig.DeadRetention <= 0 {
+		relayConfig.DeadRetention = defaultOutboxDeadRetention
+	}
+
+	return &outboxRelay{outbox: outbox, publisher: publisher, logger: logger, config: relayConfig}
+}
+
+// Run drains the outbox every poll interval until the context is cancelled.
+// Once an hour it purges the messages given up longer than DeadRetention ago.
+func (relay *outboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relay.config.PollInterval)
+	defer ticker.Stop()
+
+	var purgedAt time.Time
+
+	for {
+		if time.Since(purgedAt) >= outboxPurgeInterval {
+			if _, err := relay.PurgeDead(ctx); err != nil {
+				relay.logger.Error(ctx, "purging dead outbox messages failed", "error", err)
+			}
+
+			purgedAt = time.Now()
+		}
+
+		delivered, err := relay.Flush(ctx)
+
+		if err != nil {
+			relay.logger.Error(ctx, "draining outbox failed", "error", err)
+		}
+
+		// A batch holds one message per user, so after a delivery the next
+		// message of that user may be waiting already; don't sleep.
+		if err == nil && delivered > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush relays one batch of pending messages and returns how many were delivered.
+// Publishing stops when the claim of the batch expires, as another relay may
+// claim the remaining messages from then on; they are relayed by a later batch.
+func (relay *outboxRelay) Flush(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	claimedUntil := now.Add(relay.config.ClaimTimeout)
+
+	messages, err := relay.outbox.Claim(ctx, now, claimedUntil, relay.config.BatchSize)
+
+	if err != nil {
+		return 0, err
+	}
+
+	publishCtx, cancel := context.WithDeadline(ctx, claimedUntil)
+	defer cancel()
+
+	delivered := 0
+
+	for _, message := range messages {
+		if publishCtx.Err() != nil {
+			break
+		}
+
+		err = relay.publish(publishCtx, message)
+
+		if err == nil {
+			if err = relay.outbox.Delete(ctx, message.ID); err != nil {
+				return delivered, err
+			}
+
+			delivered++
+
+			continue
+		}
+
+		attempts := message.Attempts + 1
+		failedAt := time.Now().UTC()
+
+		if errors.Is(err, errUndeliverable) || attempts >= relay.config.MaxAttempts {
+			relay.logger.Error(ctx, "giving up on outbox message",
+				"id", message.ID, "type", message.Type, "attempts", attempts, "error", err)
+
+			if err = relay.outbox.MarkDead(ctx, message.ID, err.Error(), failedAt); err != nil {
+				return delivered, err
+			}
+
+			continue
+		}
+
+		relay.logger.Warning(ctx, "relaying outbox message failed",
+			"id", message.ID, "type", message.Type, "attempts", attempts, "error", err)
+
+		retryAt := message.RetryAt(failedAt, relay.config.RetryBackoff, relay.config.MaxRetryBackoff)
+
+		if err = relay.outbox.MarkFailed(ctx, message.ID, err.Error(), retryAt); err != nil {
+			return delivered, err
+		}
+	}
+
+	return delivered, nil
+}
+
+// PurgeDead deletes the messages given up longer than DeadRetention ago and
+// returns how many were deleted.
+func (relay *outboxRelay) PurgeDead(ctx context.Context) (int, error) {
+	purged, err := relay.outbox.PurgeDead(ctx, time.Now().UTC().Add(-relay.config.DeadRetention))
+
+	if err != nil {
+		return 0, err
+	}
+
+	if purged > 0 {
+		relay.logger.Info(ctx, "purged dead outbox messages", "count", purged)
+	}
+
+	return purged, nil
+}
+
+func (relay *outboxRelay) publish(ctx context.Context, message domain.OutboxMessage) error {
+	ctx = tracing.Extract(ctx, map[string]interface{}{
+		"traceparent": message.TraceParent,
+		"tracestate":  message.TraceState,
+	})
+
+	user, err := message.User()
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUndeliverable, err)
+	}
+
+	switch message.Type {
+	case domain.UserCreatedEvent:
+		return relay.publisher.CreateUser(ctx, user)
+	case domain.UserUpdatedEvent:
+		return relay.publisher.UpdateUserDetails(ctx, user)
+	case domain.UserDeletedEvent:
+		return relay.publisher.DeleteUser(ctx, user)
+	case domain.UserErasedEvent:
+		return relay.publisher.EraseUser(ctx, user)
+	default:
+		return fmt.Errorf("%w: unknown type %q", errUndeliverable, message.Type)
+	}
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"errors"
+	mock2 "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/internal/mock"
+	"user-service/pkg/logging"
+)
+
+type OutboxRelayTestSuite struct {
+	suite.Suite
+	MockOutbox    *mock.OutboxRepository
+	MockPublisher *mock.MessageBusPublisher
+	TestRelay     *outboxRelay
+	TestData      struct {
+		First  domain.User
+		Second domain.User
+	}
+}
+
+func (suite *OutboxRelayTestSuite) SetupSuite() {
+	outbox := new(mock.OutboxRepository)
+	publisher := new(mock.MessageBusPublisher)
+
+	cfg := &config.Config{Outbox: config.Outbox{
+		PollInterval:    time.Millisecond,
+		BatchSize:       10,
+		RetryBackoff:    time.Second,
+		MaxRetryBackoff: time.Minute,
+	}}
+
+	suite.MockOutbox = outbox
+	suite.MockPublisher = publisher
+	suite.TestRelay = NewOutboxRelay(outbox, publisher, logging.MockLogger{}, cfg)
+	suite.TestData = struct {
+		First  domain.User
+		Second domain.User
+	}{
+		First:  domain.User{ID: "first-id", Name: "first", LastName: "user", Email: "first@email.com", Version: 1},
+		Second: domain.User{ID: "second-id", Name: "second", LastName: "user", Email: "second@email.com", Version: 1},
+	}
+}
+
+func (suite *OutboxRelayTestSuite) SetupTest() {
+	suite.MockOutbox.ExpectedCalls = nil
+	suite.MockPublisher.ExpectedCalls = nil
+	suite.MockOutbox.Calls = nil
+	suite.MockPublisher.Calls = nil
+}
+
+func (suite *OutboxRelayTestSuite) message(id uint64, eventType string, user domain.User) domain.OutboxMessage {
+	message, _ := domain.NewOutboxMessage(eventType, user, time.Now().UTC().Add(-time.Second))
+	message.ID = id
+
+	return message
+}
+
+func (suite *OutboxRelayTestSuite) TestOutboxRelay_Flush() {
+	updated := suite.TestData.First
+	updated.Version = 2
+
+	messages := []domain.OutboxMessage{
+		suite.message(1, domain.UserCreatedEvent, suite.TestData.First),
+		suite.message(2, domain.UserUpdatedEvent, updated),
+		suite.message(3, domain.UserDeletedEvent, suite.TestData.Second),
+		suite.message(4, domain.UserErasedEvent, suite.TestData.Second),
+	}
+
+	suite.MockOutbox.On("Claim", mock2.Anything, mock2.Anything, 10).Return(messages, nil)
+	suite.MockOutbox.On("Delete", mock2.Anything).Return(nil)
+	suite.MockPublisher.On("CreateUser", suite.TestData.First).Return(nil)
+	suite.MockPublisher.On("UpdateUserDetails", updated).Return(nil)
+	suite.MockPublisher.On("DeleteUser", suite.TestData.Second).Return(nil)
+	suite.MockPublisher.On("EraseUser", suite.TestData.Second).Return(nil)
+
+	delivered, err := suite.TestRelay.Flush(context.Background())
+
+	suite.NoError(err)
+	suite.Equal(4, delivered)
+
+	for _, id := range []uint64{1, 2, 3, 4} {
+		suite.MockOutbox.AssertCalled(suite.T(), "Delete", id)
+	}
+}
+
+func (suite *OutboxRelayTestSuite) TestOutboxRelay_Flush_FailureContinuesWithOtherUsers() {
+	messages := []domain.OutboxMessage{
+		suite.message(1, domain.UserCreatedEvent, suite.TestData.First),
+		suite.message(2, domain.UserCreatedEvent, suite.TestData.Second),
+	}
+
+	suite.MockOutbox.On("Claim", mock2.Anything, mock2.Anything, 10).Return(messages, nil)
+	suite.MockOutbox.On("Delete", mock2.Anything).Return(nil)
+	suite.MockOutbox.On("MarkFailed", uint64(1), "broker unavailable", mock2.Anything).Return(nil)
+	suite.MockPublisher.On("CreateUser", suite.TestData.First).Return(errors.New("broker unavailable"))
+	suite.MockPublisher.On("CreateUser", suite.TestData.Second).Return(nil)
+
+	delivered, err := suite.TestRelay.Flush(context.Background())
+
+	suite.NoError(err)
+	suite.Equal(1, delivered)
+
+	suite.MockOutbox.AssertCalled(suite.T(), "Delete", uint64(2))
+	suite.MockOutbox.AssertNotCalled(suite.T(), "Delete", uint64(1))
+}
+
+func (suite *OutboxRelayTestSuite) TestOutboxRelay_Flush_MaxAttempts() {
+	message := suite.message(1, domain.UserCreatedEvent, suite.TestData.First)
+	message.Attempts = 4
+
+	cfg := &config.Config{Outbox: config.Outbox{BatchSize: 10, MaxAttempts: 5}}
+	relay := NewOutboxRelay(suite.MockOutbox, suite.MockPublisher, logging.MockLogger{}, cfg)
+
+	suite.MockOutbox.On("Claim", mock2.Anything, mock2.Anything, 10).Return([]domain.OutboxMessage{message}, nil)
+	suite.MockOutbox.On("MarkDead", uint64(1), "broker unavailable", mock2.Anything).Return(nil)
+	suite.MockPublisher.On("CreateUser", suite.TestData.First).Return(errors.New("broker unavailable"))
+
+	delivered, err := relay.Flush(context.Background())
+
+	suite.NoError(err)
+	suite.Equal(0, delivered)
+	suite.MockOutbox.AssertNumberOfCalls(suite.T(), "MarkDead", 1)
+	suite.MockOutbox.AssertNotCalled(suite.T(), "MarkFailed", mock2.Anything, mock2.Anything, mock2.Anything)
+}
+
+func (suite *OutboxRelayTestSuite) TestOutboxRelay_Flush_UnknownType() {
+	message := suite.message(1, "user.unknown", suite.TestData.First)
+
+	suite.MockOutbox.On("Claim", mock2.Anything, mock2.Anything, 10).Return([]domain.OutboxMessage{message}, nil)
+	suite.MockOutbox.On("MarkDead", uint64(1), mock2.Anything, mock2.Anything).Return(nil)
+
+	delivered, err := suite.TestRelay.Flush(context.Background())
+
+	suite.NoError(err)
+	suite.Equal(0, delivered)
+	suite.MockOutbox.AssertNumberOfCalls(suite.T(), "MarkDead", 1)
+	suite.MockOutbox.AssertNotCalled(suite.T(), "MarkFailed", mock2.Anything, mock2.Anything, mock2.Anything)
+}
+
+func (suite *OutboxRelayTestSuite) TestOutboxRelay_Flush_RetryAt() {
+	message := suite.message(1, domain.UserCreatedEvent, suite.TestData.First)
+	message.Attempts = 2
+
+	suite.MockOutbox.On("Claim", mock2.Anything, mock2.Anything, 10).Return([]domain.OutboxMessage{message}, nil)
+	suite.MockOutbox.On("MarkFailed", uint64(1), "broker unavailable", mock2.MatchedBy(func(at time.Time) bool {
+		delay := time.Until(at)
+		return delay > 3*time.Second && delay <= 4*time.Second
+	})).Return(nil)
+	suite.MockPublisher.On("CreateUser", suite.TestData.First).Return(errors.New("broker unavailable"))
+
+	_, err := suite.TestRelay.Flush(context.Background())
+
+	suite.NoError(err)
+	suite.MockOutbox.AssertNumberOfCalls(suite.T(), "MarkFailed", 1)
+}
+
+func (suite *OutboxRelayTestSuite) TestOutboxRelay_Flush_TraceContext() {
+	message := suite.message(1, domain.UserCreatedEvent, suite.TestData.First)
+	message.TraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	publisher := &contextRecordingPublisher{}
+	relay := NewOutboxRelay(suite.MockOutbox, publisher, logging.MockLogger{}, &config.Config{})
+
+	suite.MockOutbox.On("Claim", mock2.Anything, mock2.Anything, defaultOutboxBatchSize).Return([]domain.OutboxMessage{message}, nil)
+	suite.MockOutbox.On("Delete", uint64(1)).Return(nil)
+
+	_, err := relay.Flush(context.Background())
+
+	suite.NoError(err)
+
+	spanContext := trace.SpanContextFromContext(publisher.ctx)
+
+	suite.Equal("4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceID().String())
+	suite.True(spanContext.IsRemote())
+}
+
+func (suite *OutboxRelayTestSuite) TestOutboxRelay_Flush_ClaimFailed() {
+	suite.MockOutbox.On("Claim", mock2.Anything, mock2.Anything, 10).Return([]domain.OutboxMessage(nil), errors.New("database unavailable"))
+
+	_, err := suite.TestRelay.Flush(context.Background())
+
+	suite.Error(err)
+}
+
+func (suite *OutboxRelayTestSuite) TestOutboxRelay_Flush_ClaimExpired() {
+	relay := NewOutboxRelay(suite.MockOutbox, suite.MockPublisher, logging.MockLogger{}, &config.Config{Outbox: config.Outbox{
+		BatchSize:    10,
+		ClaimTimeout: time.Nanosecond,
+	}})
+
+	suite.MockOutbox.On("Claim", mock2.Anything, mock2.Anything, 10).Return([]domain.OutboxMessage{
+		suite.message(1, domain.UserCreatedEvent, suite.TestData.First),
+	}, nil)
+
+	delivered, err := relay.Flush(context.Background())
+
+	suite.NoError(err)
+	suite.Equal(0, delivered)
+
+	claim := suite.MockOutbox.Calls[0].Arguments
+	suite.Equal(time.Nanosecond, claim.Get(1).(time.Time).Sub(claim.Get(0).(time.Time)))
+
+	suite.MockPublisher.AssertNotCalled(suite.T(), "CreateUser", mock2.Anything)
+	suite.MockOutbox.AssertNotCalled(suite.T(), "Delete", mock2.Anything)
+	suite.MockOutbox.AssertNotCalled(suite.T(), "MarkFailed", mock2.Anything, mock2.Anything, mock2.Anything)
+}
+
+func (suite *OutboxRelayTestSuite) TestOutboxRelay_PurgeDead() {
+	suite.MockOutbox.On("PurgeDead", mock2.Anything).Return(2, nil)
+
+	purged, err := suite.TestRelay.PurgeDead(context.Background())
+
+	suite.NoError(err)
+	suite.Equal(2, purged)
+
+	before := suite.MockOutbox.Calls[0].Arguments.Get(0).(time.Time)
+	suite.WithinDuration(time.Now().Add(-defaultOutboxDeadRetention), before, time.Second)
+}
+
+func (suite *OutboxRelayTestSuite) TestOutboxRelay_Run() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	suite.MockOutbox.On("PurgeDead", mock2.Anything).Return(0, nil).Once()
+	suite.MockOutbox.On("Claim", mock2.Anything, mock2.Anything, 10).Return([]domain.OutboxMessage{
+		suite.message(1, domain.UserCreatedEvent, suite.TestData.First),
+	}, nil).Once()
+	suite.MockOutbox.On("Claim", mock2.Anything, mock2.Anything, 10).Return([]domain.OutboxMessage{}, nil).Run(func(mock2.Arguments) {
+		cancel()
+	})
+	suite.MockOutbox.On("Delete", uint64(1)).Return(nil)
+	suite.MockPublisher.On("CreateUser", suite.TestData.First).Return(nil)
+
+	done := make(chan struct{})
+
+	go func() {
+		suite.TestRelay.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.Fail("relay did not stop after the context was cancelled")
+	}
+
+	suite.MockPublisher.AssertCalled(suite.T(), "CreateUser", suite.TestData.First)
+	suite.MockOutbox.AssertNumberOfCalls(suite.T(), "PurgeDead", 1)
+}
+
+type contextRecordingPublisher struct {
+	ctx context.Context
+}
+
+func (pub *contextRecordingPublisher) CreateUser(ctx context.Context, user domain.User) error {
+	pub.ctx = ctx
+	return nil
+}
+
+func (pub *contextRecordingPublisher) UpdateUserDetails(ctx context.Context, user domain.User) error {
+	pub.ctx = ctx
+	return nil
+}
+
+func (pub *contextRecordingPublisher) DeleteUser(ctx context.Context, user domain.User) error {
+	pub.ctx = ctx
+	return nil
+}
+
+func (pub *contextRecordingPublisher) EraseUser(ctx context.Context, user domain.User) error {
+	pub.ctx = ctx
+	return nil
+}
+
+func TestUnit_OutboxRelayTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxRelayTestSuite))
+}
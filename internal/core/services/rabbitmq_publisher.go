@@ -2,14 +2,17 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"user-service/config"
 	"user-service/internal/core/domain"
+	"user-service/pkg/cloudevents"
 	"user-service/pkg/rabbitmq"
+	"user-service/pkg/tracing"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -31,35 +34,65 @@ func (rmq *rabbitmqPublisher) UpdateUserDetails(ctx context.Context, user domain
 	return rmq.publishJson(ctx, "update", user)
 }
 
-func (rmq *rabbitmqPublisher) publishJson(ctx context.Context, topic string, body interface{}) error {
-	js, err := json.Marshal(body)
+func (rmq *rabbitmqPublisher) DeleteUser(ctx context.Context, user domain.User) error {
+	return rmq.publishJson(ctx, "delete", user)
+}
+
+func (rmq *rabbitmqPublisher) EraseUser(ctx context.Context, user domain.User) error {
+	return rmq.publishJson(ctx, "erased", user)
+}
+
+func (rmq *rabbitmqPublisher) publishJson(ctx context.Context, topic string, user domain.User) error {
+	event, err := newUserEvent(rmq.config, topic, user)
 
 	if err != nil {
 		return err
 	}
 
-	if rmq.tracer != nil {
-		_, span := rmq.tracer.Start(ctx, "publish")
+	publishing := amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		MessageId:    event.ID,
+		Timestamp:    event.Time,
+	}
+
+	if isStructuredMode(rmq.config) {
+		publishing.ContentType = cloudevents.StructuredContentType
+		publishing.Body, err = event.Structured()
+
+		if err != nil {
+			return err
+		}
+	} else {
+		publishing.ContentType = event.DataContentType
+		publishing.Headers = event.Headers()
+		publishing.Body = event.Data
+	}
+
+	routingKey := fmt.Sprintf("user.%s", topic)
 
-		span.AddEvent(
-			"Published message to rabbitmq",
-			trace.WithAttributes(
-				attribute.String("topic", topic),
-				attribute.String("body", string(js))))
-		span.End()
+	ctx, span := rmq.tracer.Start(ctx, routingKey+" send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("rabbitmq"),
+			semconv.MessagingDestinationKey.String(rmq.config.RabbitMQ.Exchange),
+			semconv.MessagingDestinationKindTopic,
+			semconv.MessagingRabbitmqRoutingKeyKey.String(routingKey),
+			semconv.MessagingMessageIDKey.String(event.ID),
+			attribute.String("event.type", event.Type)))
+	defer span.End()
+
+	if publishing.Headers == nil {
+		publishing.Headers = amqp.Table{}
 	}
 
-	err = rmq.rabbitmq.Channel.Publish(
-		rmq.config.RabbitMQ.Exchange,
-		fmt.Sprintf("user.%s", topic),
-		false,
-		false,
-		amqp.Publishing{
-			DeliveryMode: amqp.Persistent,
-			ContentType:  "text/plain",
-			Body:         js,
-		},
-	)
+	tracing.Inject(ctx, publishing.Headers)
+
+	err = rmq.rabbitmq.Publish(ctx, rmq.config.RabbitMQ.Exchange, routingKey, publishing)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
 
 	return err
 }
@@ -10,6 +10,9 @@ import (
 	"user-service/config"
 	"user-service/internal/core/domain"
 	"user-service/internal/core/interfaces"
+	"user-service/pkg/cloudevents"
+	"user-service/pkg/events"
+	"user-service/pkg/logging"
 	"user-service/pkg/rabbitmq"
 )
 
@@ -31,7 +34,7 @@ func (suite *RabbitMQPublisherTestSuite) SetupSuite() {
 		panic(errors.WithStack(err))
 	}
 
-	rmqServer, err := rabbitmq.NewRabbitMQ(cfg)
+	rmqServer, err := rabbitmq.NewRabbitMQ(cfg, logging.MockLogger{})
 
 	if err != nil {
 		panic(errors.WithStack(err))
@@ -56,7 +59,7 @@ func (suite *RabbitMQPublisherTestSuite) SetupSuite() {
 }
 
 func (suite *RabbitMQPublisherTestSuite) TestRabbitMQPublisher_CreateUser() {
-	ch, err := suite.TestRabbitMQ.Connection.Channel()
+	ch, err := suite.TestRabbitMQ.OpenChannel()
 
 	suite.NoError(err)
 
@@ -95,19 +98,27 @@ func (suite *RabbitMQPublisherTestSuite) TestRabbitMQPublisher_CreateUser() {
 
 	suite.NoError(err)
 
-	err = suite.TestPublisher.CreateUser(context.Background(), suite.TestData.User)
+	ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+
+	err = suite.TestPublisher.CreateUser(ctx, suite.TestData.User)
 
 	suite.NoError(err)
 
 	for msg := range msgs {
 		suite.Equal("user.create", msg.RoutingKey)
+		suite.Contains(msg.Headers["traceparent"], span.SpanContext().TraceID().String())
+		suite.Equal(cloudevents.JSONContentType, msg.ContentType)
+		suite.Equal("bikepack.user.created", msg.Headers["cloudEvents:type"])
+		suite.Equal(suite.TestData.User.ID, msg.Headers["cloudEvents:subject"])
+		suite.Equal(msg.MessageId, msg.Headers["cloudEvents:id"])
 
-		var user domain.User
+		var user events.UserV1
 
 		err = json.Unmarshal(msg.Body, &user)
 		suite.NoError(err)
 
-		suite.Equal(suite.TestData.User, user)
+		suite.Equal(events.NewUserV1(suite.TestData.User), user)
 
 		err = msg.Ack(true)
 
@@ -122,7 +133,7 @@ func (suite *RabbitMQPublisherTestSuite) TestRabbitMQPublisher_CreateUser() {
 }
 
 func (suite *RabbitMQPublisherTestSuite) TestRabbitMQPublisher_UpdateUserDetails() {
-	ch, err := suite.TestRabbitMQ.Connection.Channel()
+	ch, err := suite.TestRabbitMQ.OpenChannel()
 
 	suite.NoError(err)
 
@@ -168,12 +179,12 @@ func (suite *RabbitMQPublisherTestSuite) TestRabbitMQPublisher_UpdateUserDetails
 	for msg := range msgs {
 		suite.Equal("user.update", msg.RoutingKey)
 
-		var user domain.User
+		var user events.UserV1
 
 		err = json.Unmarshal(msg.Body, &user)
 		suite.NoError(err)
 
-		suite.Equal(suite.TestData.User, user)
+		suite.Equal(events.NewUserV1(suite.TestData.User), user)
 
 		err = msg.Ack(true)
 
@@ -187,6 +198,108 @@ func (suite *RabbitMQPublisherTestSuite) TestRabbitMQPublisher_UpdateUserDetails
 	}
 }
 
+func (suite *RabbitMQPublisherTestSuite) TestRabbitMQPublisher_CreateUser_Structured() {
+	cfg := *suite.Cfg
+	cfg.Events.Mode = cloudevents.StructuredMode
+
+	publisher := NewRabbitMQPublisher(suite.TestRabbitMQ, trace.NewTracerProvider(), &cfg)
+
+	ch, err := suite.TestRabbitMQ.OpenChannel()
+
+	suite.NoError(err)
+
+	queue, err := ch.QueueDeclare(
+		"",
+		false,
+		false,
+		true,
+		false,
+		nil,
+	)
+
+	suite.NoError(err)
+
+	err = ch.QueueBind(
+		queue.Name,
+		"user.create",
+		suite.Cfg.RabbitMQ.Exchange,
+		false,
+		nil)
+
+	suite.NoError(err)
+
+	msgs, err := ch.Consume(
+		queue.Name,
+		"",
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+
+	suite.NoError(err)
+
+	err = publisher.CreateUser(context.Background(), suite.TestData.User)
+
+	suite.NoError(err)
+
+	for msg := range msgs {
+		suite.Equal(cloudevents.StructuredContentType, msg.ContentType)
+
+		var event cloudevents.Event
+
+		err = json.Unmarshal(msg.Body, &event)
+		suite.NoError(err)
+
+		suite.Equal(cloudevents.SpecVersion, event.SpecVersion)
+		suite.Equal("bikepack.user.created", event.Type)
+		suite.Equal(suite.TestData.User.ID, event.Subject)
+		suite.Equal("1", event.Extensions["schemaversion"])
+
+		var user events.UserV1
+
+		err = json.Unmarshal(event.Data, &user)
+		suite.NoError(err)
+
+		suite.Equal(events.NewUserV1(suite.TestData.User), user)
+
+		err = msg.Ack(true)
+
+		suite.NoError(err)
+
+		err = ch.Close()
+
+		suite.NoError(err)
+
+		return
+	}
+}
+
+func (suite *RabbitMQPublisherTestSuite) TestRabbitMQPublisher_Unroutable() {
+	cfg := *suite.Cfg
+	cfg.RabbitMQ.Exchange = "unroutable-test"
+
+	ch, err := suite.TestRabbitMQ.OpenChannel()
+
+	suite.NoError(err)
+
+	err = ch.ExchangeDeclare(cfg.RabbitMQ.Exchange, "topic", false, true, false, false, nil)
+
+	suite.NoError(err)
+
+	publisher := NewRabbitMQPublisher(suite.TestRabbitMQ, trace.NewTracerProvider(), &cfg)
+
+	err = publisher.DeleteUser(context.Background(), suite.TestData.User)
+
+	var returned *rabbitmq.ReturnedError
+	suite.ErrorAs(err, &returned)
+	suite.Equal("user.delete", returned.RoutingKey)
+
+	suite.NoError(ch.ExchangeDelete(cfg.RabbitMQ.Exchange, false, false))
+	suite.NoError(ch.Close())
+}
+
 func TestIntegration_RabbitMQPublisherTestSuite(t *testing.T) {
 	testSuite := new(RabbitMQPublisherTestSuite)
 	suite.Run(t, testSuite)
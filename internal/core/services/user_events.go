@@ -0,0 +1,67 @@
+package services
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"strconv"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/pkg/cloudevents"
+	"user-service/pkg/events"
+)
+
+const (
+	defaultEventSource     = "/bikepack/user-service"
+	schemaVersionExtension = "schemaversion"
+)
+
+var eventTypes = map[string]string{
+	"create": events.UserCreated,
+	"update": events.UserUpdated,
+	"delete": events.UserDeleted,
+	"erased": events.UserErased,
+}
+
+// newUserEvent wraps the current payload version of the user in a CloudEvent. The id is derived from the event
+// type, the user and its version, so a message that is relayed more than once
+// keeps the same id and consumers can deduplicate on it.
+func newUserEvent(cfg *config.Config, topic string, user domain.User) (cloudevents.Event, error) {
+	eventType, ok := eventTypes[topic]
+
+	if !ok {
+		return cloudevents.Event{}, fmt.Errorf("unknown user event topic %q", topic)
+	}
+
+	definition, ok := events.Current(eventType)
+
+	if !ok {
+		return cloudevents.Event{}, fmt.Errorf("no payload defined for event type %q", eventType)
+	}
+
+	source := cfg.Events.Source
+
+	if source == "" {
+		source = defaultEventSource
+	}
+
+	id := uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("%s/%s/%s/%d", source, eventType, user.ID, user.Version)))
+
+	event, err := cloudevents.NewJSONEvent(id.String(), source, eventType, user.ID, time.Now().UTC(), definition.Payload(user))
+
+	if err != nil {
+		return cloudevents.Event{}, err
+	}
+
+	event.Extensions = map[string]string{schemaVersionExtension: strconv.Itoa(definition.Version)}
+
+	if cfg.Events.SchemaURL != "" {
+		event.DataSchema = fmt.Sprintf("%s/%s", cfg.Events.SchemaURL, definition.Path())
+	}
+
+	return event, nil
+}
+
+func isStructuredMode(cfg *config.Config) bool {
+	return cfg.Events.Mode == cloudevents.StructuredMode
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/pkg/events"
+)
+
+type UserEventsTestSuite struct {
+	suite.Suite
+	Cfg      *config.Config
+	TestData struct {
+		User domain.User
+	}
+}
+
+func (suite *UserEventsTestSuite) SetupSuite() {
+	suite.Cfg = &config.Config{Events: config.Events{
+		Source:    "/test/user-service",
+		SchemaURL: "https://example.com/schemas",
+	}}
+	suite.TestData.User = domain.User{
+		ID:       "test-id",
+		Name:     "test-name",
+		LastName: "test-lastname",
+		Email:    "test@email.com",
+		Version:  2,
+	}
+}
+
+func (suite *UserEventsTestSuite) TestUserEvents_NewUserEvent() {
+	event, err := newUserEvent(suite.Cfg, "create", suite.TestData.User)
+
+	suite.NoError(err)
+	suite.Equal("bikepack.user.created", event.Type)
+	suite.Equal("/test/user-service", event.Source)
+	suite.Equal("test-id", event.Subject)
+	suite.Equal("https://example.com/schemas/bikepack.user.created/1", event.DataSchema)
+	suite.Equal("1", event.Extensions["schemaversion"])
+	suite.NotEmpty(event.ID)
+
+	var payload events.UserV1
+
+	suite.NoError(json.Unmarshal(event.Data, &payload))
+	suite.Equal(events.NewUserV1(suite.TestData.User), payload)
+}
+
+func (suite *UserEventsTestSuite) TestUserEvents_NewUserEvent_Erased() {
+	erased := suite.TestData.User
+	erased.Erase("admin-id", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	event, err := newUserEvent(suite.Cfg, "erased", erased)
+
+	suite.NoError(err)
+	suite.JSONEq(`{
+		"id": "test-id",
+		"name": "erased",
+		"last_name": "`+erased.LastName+`",
+		"email": "`+erased.Email+`",
+		"version": 2,
+		"erased_at": "2022-01-01T00:00:00Z",
+		"erased_by": "admin-id"
+	}`, string(event.Data))
+}
+
+func (suite *UserEventsTestSuite) TestUserEvents_NewUserEvent_StableID() {
+	first, _ := newUserEvent(suite.Cfg, "update", suite.TestData.User)
+	second, _ := newUserEvent(suite.Cfg, "update", suite.TestData.User)
+
+	suite.Equal(first.ID, second.ID)
+
+	next := suite.TestData.User
+	next.Version++
+
+	third, _ := newUserEvent(suite.Cfg, "update", next)
+	deleted, _ := newUserEvent(suite.Cfg, "delete", suite.TestData.User)
+
+	suite.NotEqual(first.ID, third.ID)
+	suite.NotEqual(first.ID, deleted.ID)
+}
+
+func (suite *UserEventsTestSuite) TestUserEvents_NewUserEvent_DefaultSource() {
+	event, err := newUserEvent(&config.Config{}, "erased", suite.TestData.User)
+
+	suite.NoError(err)
+	suite.Equal("bikepack.user.erased", event.Type)
+	suite.Equal(defaultEventSource, event.Source)
+	suite.Empty(event.DataSchema)
+}
+
+func (suite *UserEventsTestSuite) TestUserEvents_NewUserEvent_UnknownTopic() {
+	_, err := newUserEvent(suite.Cfg, "unknown", suite.TestData.User)
+
+	suite.Error(err)
+}
+
+func TestUnit_UserEventsTestSuite(t *testing.T) {
+	suite.Run(t, new(UserEventsTestSuite))
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 	"user-service/internal/core/domain"
 	"user-service/internal/core/interfaces"
 )
@@ -19,14 +21,18 @@ func NewUserService(riderRepository interfaces.UserRepository, messagePublisher
 	}
 }
 
-func (srv *userService) GetAll(ctx context.Context) ([]domain.User, error) {
-	return srv.userRepository.GetAll(ctx)
+func (srv *userService) GetAll(ctx context.Context, query domain.UserQuery) (domain.UserPage, error) {
+	return srv.userRepository.GetAll(ctx, query)
 }
 
 func (srv *userService) Get(ctx context.Context, id string) (domain.User, error) {
 	return srv.userRepository.Get(ctx, id)
 }
 
+func (srv *userService) Search(ctx context.Context, query string, page domain.SearchPage) (domain.UserSearchResult, error) {
+	return srv.userRepository.Search(ctx, query, page)
+}
+
 func (srv *userService) Create(ctx context.Context, id, name, lastName, email string) (domain.User, error) {
 	user, err := domain.NewUser(id, name, lastName, email)
 
@@ -34,13 +40,19 @@ func (srv *userService) Create(ctx context.Context, id, name, lastName, email st
 		return domain.User{}, err
 	}
 
-	user, err = srv.userRepository.Save(ctx, user)
+	err = srv.userRepository.Transaction(ctx, func(ctx context.Context) error {
+		user, err = srv.userRepository.Save(ctx, user)
 
-	if err != nil {
-		return domain.User{}, errors.New("saving new user failed")
-	}
+		if errors.Is(err, domain.ErrConflict) {
+			return err
+		}
+
+		if err != nil {
+			return errors.New("saving new user failed")
+		}
 
-	err = srv.messagePublisher.CreateUser(ctx, user)
+		return srv.messagePublisher.CreateUser(ctx, user)
+	})
 
 	if err != nil {
 		return domain.User{}, err
@@ -49,37 +61,107 @@ func (srv *userService) Create(ctx context.Context, id, name, lastName, email st
 	return user, nil
 }
 
-func (srv *userService) UpdateUserDetails(ctx context.Context, id string, name, lastName, email string) (domain.User, error) {
+func (srv *userService) UpdateUserDetails(ctx context.Context, id string, version int64, name, lastName, email string) (domain.User, error) {
 	existing, err := srv.Get(ctx, id)
 	updated := existing
 
 	if err != nil {
-		return domain.User{}, errors.New("could not find user with id")
+		return domain.User{}, err
 	}
 
-	if name != "" {
-		updated.Name = name
+	if existing.IsErased() {
+		return existing, domain.NewConflictError("", "user has been erased")
 	}
 
-	if lastName != "" {
-		updated.LastName = lastName
+	if err = existing.CheckVersion(version); err != nil {
+		return existing, err
 	}
 
-	if email != "" {
-		updated.Email = email
+	if err = updated.ChangeName(name, lastName); err != nil {
+		return existing, err
 	}
 
-	updated, err = srv.userRepository.Update(ctx, updated)
+	if err = updated.ChangeEmail(email); err != nil {
+		return existing, err
+	}
+
+	err = srv.userRepository.Transaction(ctx, func(ctx context.Context) error {
+		updated, err = srv.userRepository.Update(ctx, updated)
+
+		if errors.Is(err, domain.ErrConflict) || errors.Is(err, domain.ErrPreconditionFailed) {
+			return err
+		}
+
+		if err != nil {
+			return errors.New("saving new user failed")
+		}
+
+		return srv.messagePublisher.UpdateUserDetails(ctx, updated)
+	})
 
 	if err != nil {
-		return existing, errors.New("saving new user failed")
+		return existing, err
 	}
 
-	err = srv.messagePublisher.UpdateUserDetails(ctx, updated)
+	return updated, nil
+}
+
+func (srv *userService) Delete(ctx context.Context, id string) error {
+	user, err := srv.Get(ctx, id)
 
 	if err != nil {
-		return updated, err
+		return err
 	}
 
-	return updated, nil
+	return srv.userRepository.Transaction(ctx, func(ctx context.Context) error {
+		err = srv.userRepository.Delete(ctx, id)
+
+		if err != nil {
+			return errors.New("deleting user failed")
+		}
+
+		return srv.messagePublisher.DeleteUser(ctx, user)
+	})
+}
+
+// Erase also erases deleted users, as deleting a user keeps its data.
+func (srv *userService) Erase(ctx context.Context, id, requestedBy string) (domain.User, error) {
+	user, err := srv.userRepository.GetUnscoped(ctx, id)
+
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if user.IsErased() {
+		return user, nil
+	}
+
+	user.Erase(requestedBy, time.Now().UTC())
+
+	err = srv.userRepository.Transaction(ctx, func(ctx context.Context) error {
+		user, err = srv.userRepository.UpdateUnscoped(ctx, user)
+
+		if err != nil {
+			return fmt.Errorf("erasing user failed: %w", err)
+		}
+
+		return srv.messagePublisher.EraseUser(ctx, user)
+	})
+
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// Export includes deleted users, as deleting a user keeps its data.
+func (srv *userService) Export(ctx context.Context, id string) (domain.UserExport, error) {
+	user, err := srv.userRepository.GetUnscoped(ctx, id)
+
+	if err != nil {
+		return domain.UserExport{}, err
+	}
+
+	return domain.NewUserExport(user, time.Now().UTC()), nil
 }
@@ -6,6 +6,7 @@ import (
 	mock2 "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 	"user-service/internal/core/domain"
 	"user-service/internal/core/interfaces"
 	"user-service/internal/mock"
@@ -45,18 +46,23 @@ func (suite *UserServiceTestSuite) SetupSuite() {
 func (suite *UserServiceTestSuite) SetupTest() {
 	suite.MockPublisher.ExpectedCalls = nil
 	suite.MockRepository.ExpectedCalls = nil
+	suite.MockPublisher.Calls = nil
+	suite.MockRepository.Calls = nil
 }
 
 func (suite *UserServiceTestSuite) TestUserService_GetAll() {
-	suite.MockRepository.On("GetAll").Return([]domain.User{suite.TestData.User}, nil)
+	query, _ := domain.NewUserQuery(0, "", "", "", "")
 
-	result, err := suite.TestService.GetAll(context.Background())
+	suite.MockRepository.On("GetAll", query).Return(domain.UserPage{Users: []domain.User{suite.TestData.User}, Total: 1}, nil)
+
+	result, err := suite.TestService.GetAll(context.Background(), query)
 
 	suite.NoError(err)
 
-	suite.MockRepository.AssertCalled(suite.T(), "GetAll")
-	suite.Equal(1, len(result))
-	suite.EqualValues(suite.TestData.User, result[0])
+	suite.MockRepository.AssertCalled(suite.T(), "GetAll", query)
+	suite.Equal(1, len(result.Users))
+	suite.EqualValues(1, result.Total)
+	suite.EqualValues(suite.TestData.User, result.Users[0])
 }
 
 func (suite *UserServiceTestSuite) TestUserService_Get() {
@@ -71,16 +77,29 @@ func (suite *UserServiceTestSuite) TestUserService_Get() {
 }
 
 func (suite *UserServiceTestSuite) TestUserService_Get_NotFound() {
-	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(domain.User{}, errors.New("could not find user"))
+	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(domain.User{}, domain.NewNotFoundError("user", suite.TestData.User.ID))
 
 	result, err := suite.TestService.Get(context.Background(), suite.TestData.User.ID)
 
-	suite.Error(err)
+	suite.ErrorIs(err, domain.ErrNotFound)
 	suite.EqualValues(domain.User{}, result)
 
 	suite.MockRepository.AssertCalled(suite.T(), "Get", suite.TestData.User.ID)
 }
 
+func (suite *UserServiceTestSuite) TestUserService_Search() {
+	page, _ := domain.NewSearchPage(1, 10)
+
+	suite.MockRepository.On("Search", "test", page).Return(domain.UserSearchResult{Users: []domain.User{suite.TestData.User}, Total: 1}, nil)
+
+	result, err := suite.TestService.Search(context.Background(), "test", page)
+
+	suite.NoError(err)
+
+	suite.EqualValues(1, result.Total)
+	suite.EqualValues(suite.TestData.User, result.Users[0])
+}
+
 func (suite *UserServiceTestSuite) TestUserService_Create() {
 	suite.MockRepository.On("GetUser", suite.TestData.User.ID).Return(suite.TestData.User, nil)
 	suite.MockRepository.On("Save", mock2.Anything).Return(suite.TestData.User, nil)
@@ -113,22 +132,98 @@ func (suite *UserServiceTestSuite) TestUserService_Create_CouldNotSave() {
 	suite.MockPublisher.AssertNotCalled(suite.T(), "CreateUser")
 }
 
+func (suite *UserServiceTestSuite) TestUserService_Create_CouldNotPublish() {
+	suite.MockRepository.On("Save", mock2.Anything).Return(suite.TestData.User, nil)
+	suite.MockPublisher.On("CreateUser", suite.TestData.User).Return(errors.New("could not write outbox"))
+
+	result, err := suite.TestService.Create(context.Background(), suite.TestData.User.ID, suite.TestData.User.Name, suite.TestData.User.LastName, suite.TestData.User.Email)
+
+	suite.Error(err)
+	suite.Equal(domain.User{}, result)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_Create_Conflict() {
+	suite.MockRepository.On("Save", mock2.Anything).Return(domain.User{}, domain.NewConflictError("email", "email is already in use"))
+
+	_, err := suite.TestService.Create(context.Background(), suite.TestData.User.ID, suite.TestData.User.Name, suite.TestData.User.LastName, suite.TestData.User.Email)
+
+	var conflictErr *domain.ConflictError
+	suite.ErrorAs(err, &conflictErr)
+	suite.Equal("email", conflictErr.Field)
+
+	suite.MockPublisher.AssertNotCalled(suite.T(), "CreateUser", mock2.Anything)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_UpdateUserDetails_StaleVersion() {
+	existing := suite.TestData.User
+	existing.Version = 3
+
+	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(existing, nil)
+
+	_, err := suite.TestService.UpdateUserDetails(context.Background(), existing.ID, 2, "new-name", existing.LastName, existing.Email)
+
+	suite.ErrorIs(err, domain.ErrPreconditionFailed)
+	suite.MockRepository.AssertNotCalled(suite.T(), "Update", mock2.Anything)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_UpdateUserDetails_ConcurrentUpdate() {
+	updated := suite.TestData.User
+	updated.Name = "new-name"
+
+	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+	suite.MockRepository.On("Update", updated).Return(domain.User{}, domain.NewPreconditionFailedError("user has been modified"))
+
+	_, err := suite.TestService.UpdateUserDetails(context.Background(), updated.ID, 0, updated.Name, updated.LastName, updated.Email)
+
+	suite.ErrorIs(err, domain.ErrPreconditionFailed)
+	suite.MockPublisher.AssertNotCalled(suite.T(), "UpdateUserDetails", mock2.Anything)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_UpdateUserDetails_Conflict() {
+	updated := suite.TestData.User
+	updated.Email = "taken@email.com"
+
+	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+	suite.MockRepository.On("Update", updated).Return(domain.User{}, domain.NewConflictError("email", "email is already in use"))
+
+	result, err := suite.TestService.UpdateUserDetails(context.Background(), updated.ID, 0, updated.Name, updated.LastName, updated.Email)
+
+	suite.ErrorIs(err, domain.ErrConflict)
+	suite.EqualValues(suite.TestData.User, result)
+	suite.MockPublisher.AssertNotCalled(suite.T(), "UpdateUserDetails", mock2.Anything)
+}
+
 func (suite *UserServiceTestSuite) TestUserService_UpdateUserDetails() {
 	updated := suite.TestData.User
 	updated.Name = "new-name"
 	updated.LastName = "new-last-name"
+	updated.Email = "new@email.com"
 
 	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
 	suite.MockRepository.On("Update", updated).Return(updated, nil)
 	suite.MockPublisher.On("UpdateUserDetails", updated).Return(nil)
 
-	result, err := suite.TestService.UpdateUserDetails(context.Background(), updated.ID, updated.Name, updated.LastName, updated.Email)
+	result, err := suite.TestService.UpdateUserDetails(context.Background(), updated.ID, 0, "New-Name", "New-Last-Name", updated.Email)
 
 	suite.NoError(err)
 
 	suite.EqualValues(updated, result)
 }
 
+func (suite *UserServiceTestSuite) TestUserService_UpdateUserDetails_CouldNotPublish() {
+	updated := suite.TestData.User
+	updated.Name = "new-name"
+
+	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+	suite.MockRepository.On("Update", updated).Return(updated, nil)
+	suite.MockPublisher.On("UpdateUserDetails", updated).Return(errors.New("could not write outbox"))
+
+	result, err := suite.TestService.UpdateUserDetails(context.Background(), updated.ID, 0, updated.Name, updated.LastName, updated.Email)
+
+	suite.Error(err)
+	suite.EqualValues(suite.TestData.User, result)
+}
+
 func (suite *UserServiceTestSuite) TestUserService_UpdateServiceArea_UserNotFound() {
 	updated := suite.TestData.User
 	updated.Name = "new-name"
@@ -136,7 +231,7 @@ func (suite *UserServiceTestSuite) TestUserService_UpdateServiceArea_UserNotFoun
 
 	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(domain.User{}, errors.New("user not found"))
 
-	_, err := suite.TestService.UpdateUserDetails(context.Background(), updated.ID, updated.Name, updated.LastName, updated.Email)
+	_, err := suite.TestService.UpdateUserDetails(context.Background(), updated.ID, 0, updated.Name, updated.LastName, updated.Email)
 
 	suite.Error(err)
 }
@@ -149,12 +244,150 @@ func (suite *UserServiceTestSuite) TestUserService_UpdateServiceArea_CouldNotUpd
 	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
 	suite.MockRepository.On("Update", updated).Return(suite.TestData.User, errors.New("could not update user"))
 
-	result, err := suite.TestService.UpdateUserDetails(context.Background(), updated.ID, updated.Name, updated.LastName, updated.Email)
+	result, err := suite.TestService.UpdateUserDetails(context.Background(), updated.ID, 0, updated.Name, updated.LastName, updated.Email)
 
 	suite.Error(err)
 	suite.EqualValues(suite.TestData.User, result)
 }
 
+func (suite *UserServiceTestSuite) TestUserService_Delete() {
+	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+	suite.MockRepository.On("Delete", suite.TestData.User.ID).Return(nil)
+	suite.MockPublisher.On("DeleteUser", suite.TestData.User).Return(nil)
+
+	err := suite.TestService.Delete(context.Background(), suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.MockRepository.AssertCalled(suite.T(), "Delete", suite.TestData.User.ID)
+	suite.MockPublisher.AssertCalled(suite.T(), "DeleteUser", suite.TestData.User)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_Delete_UserNotFound() {
+	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(domain.User{}, errors.New("user not found"))
+
+	err := suite.TestService.Delete(context.Background(), suite.TestData.User.ID)
+
+	suite.Error(err)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_Delete_CouldNotDelete() {
+	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+	suite.MockRepository.On("Delete", suite.TestData.User.ID).Return(errors.New("could not delete user"))
+
+	err := suite.TestService.Delete(context.Background(), suite.TestData.User.ID)
+
+	suite.Error(err)
+
+	suite.MockPublisher.AssertNotCalled(suite.T(), "DeleteUser", suite.TestData.User)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_UpdateUserDetails_MissingData() {
+	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+
+	result, err := suite.TestService.UpdateUserDetails(context.Background(), suite.TestData.User.ID, 0, "new-name", "", suite.TestData.User.Email)
+
+	suite.Error(err)
+	suite.EqualValues(suite.TestData.User, result)
+	suite.MockRepository.AssertNotCalled(suite.T(), "Update", mock2.Anything)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_UpdateUserDetails_InvalidEmail() {
+	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+
+	_, err := suite.TestService.UpdateUserDetails(context.Background(), suite.TestData.User.ID, 0, suite.TestData.User.Name, suite.TestData.User.LastName, "not-an-email")
+
+	var validationErr *domain.ValidationError
+	suite.ErrorAs(err, &validationErr)
+	suite.Equal("email", validationErr.Field)
+	suite.MockRepository.AssertNotCalled(suite.T(), "Update", mock2.Anything)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_UpdateUserDetails_Erased() {
+	erased := suite.TestData.User
+	erased.Erase("test-id", time.Now())
+
+	suite.MockRepository.On("Get", suite.TestData.User.ID).Return(erased, nil)
+
+	_, err := suite.TestService.UpdateUserDetails(context.Background(), erased.ID, 0, "new-name", erased.LastName, erased.Email)
+
+	suite.ErrorIs(err, domain.ErrConflict)
+	suite.MockRepository.AssertNotCalled(suite.T(), "Update", mock2.Anything)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_Erase() {
+	erased := suite.TestData.User
+	erased.Erase("admin-id", time.Now())
+
+	suite.MockRepository.On("GetUnscoped", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+	suite.MockRepository.On("UpdateUnscoped", mock2.MatchedBy(func(user domain.User) bool {
+		return user.IsErased() && user.ErasedBy == "admin-id" && user.Email == erased.Email
+	})).Return(erased, nil)
+	suite.MockPublisher.On("EraseUser", erased).Return(nil)
+
+	result, err := suite.TestService.Erase(context.Background(), suite.TestData.User.ID, "admin-id")
+
+	suite.NoError(err)
+
+	suite.Equal(suite.TestData.User.ID, result.ID)
+	suite.NotEqual(suite.TestData.User.Email, result.Email)
+	suite.Equal("admin-id", result.ErasedBy)
+	suite.True(result.IsErased())
+	suite.MockPublisher.AssertCalled(suite.T(), "EraseUser", result)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_Erase_AlreadyErased() {
+	erased := suite.TestData.User
+	erased.Erase("admin-id", time.Now())
+
+	suite.MockRepository.On("GetUnscoped", suite.TestData.User.ID).Return(erased, nil)
+
+	result, err := suite.TestService.Erase(context.Background(), suite.TestData.User.ID, "other-id")
+
+	suite.NoError(err)
+
+	suite.Equal(erased, result)
+	suite.MockRepository.AssertNotCalled(suite.T(), "UpdateUnscoped", mock2.Anything)
+	suite.MockPublisher.AssertNotCalled(suite.T(), "EraseUser", mock2.Anything)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_Erase_StaleVersion() {
+	suite.MockRepository.On("GetUnscoped", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+	suite.MockRepository.On("UpdateUnscoped", mock2.Anything).Return(domain.User{}, domain.NewPreconditionFailedError("user has been modified"))
+
+	_, err := suite.TestService.Erase(context.Background(), suite.TestData.User.ID, "admin-id")
+
+	suite.ErrorIs(err, domain.ErrPreconditionFailed)
+	suite.MockPublisher.AssertNotCalled(suite.T(), "EraseUser", mock2.Anything)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_Erase_UserNotFound() {
+	suite.MockRepository.On("GetUnscoped", suite.TestData.User.ID).Return(domain.User{}, errors.New("user not found"))
+
+	_, err := suite.TestService.Erase(context.Background(), suite.TestData.User.ID, "admin-id")
+
+	suite.Error(err)
+}
+
+func (suite *UserServiceTestSuite) TestUserService_Export() {
+	suite.MockRepository.On("GetUnscoped", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+
+	result, err := suite.TestService.Export(context.Background(), suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.EqualValues(suite.TestData.User, result.User)
+	suite.False(result.ExportedAt.IsZero())
+}
+
+func (suite *UserServiceTestSuite) TestUserService_Export_UserNotFound() {
+	suite.MockRepository.On("GetUnscoped", suite.TestData.User.ID).Return(domain.User{}, errors.New("user not found"))
+
+	_, err := suite.TestService.Export(context.Background(), suite.TestData.User.ID)
+
+	suite.Error(err)
+}
+
 func TestUnit_UserServiceTestSuite(t *testing.T) {
 	testSuite := new(UserServiceTestSuite)
 	suite.Run(t, testSuite)
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+	"user-service/config"
+	"user-service/pkg/cloudevents"
+	"user-service/pkg/logging"
+	"user-service/pkg/tracing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+)
+
+type receiver interface {
+	ReceiveMessages(ctx context.Context, maxMessages int, options *azservicebus.ReceiveMessagesOptions) ([]*azservicebus.ReceivedMessage, error)
+	CompleteMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.CompleteMessageOptions) error
+	AbandonMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.AbandonMessageOptions) error
+	DeadLetterMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.DeadLetterOptions) error
+	Close(ctx context.Context) error
+}
+
+// AzureConsumer receives the identity events from a subscription on the topic
+// of every event type. Messages are completed once handled, poison messages
+// and messages that failed MaxDeliveries times are moved to the dead letter
+// queue of the subscription with the failure reason and attempt count. Other
+// failed messages are abandoned and redelivered.
+type AzureConsumer struct {
+	newReceiver func(topic string) (receiver, error)
+	handler     *IdentityEventHandler
+	logger      logging.Logger
+	retryDelay  time.Duration
+	config      config.Consumer
+}
+
+func NewAzureConsumer(client *azservicebus.Client, handler *IdentityEventHandler, logger logging.Logger, cfg *config.Config) *AzureConsumer {
+	consumerConfig := consumerConfig(cfg)
+
+	return &AzureConsumer{
+		newReceiver: func(topic string) (receiver, error) {
+			return client.NewReceiverForSubscription(topic, consumerConfig.Subscription, nil)
+		},
+		handler:    handler,
+		logger:     logger,
+		retryDelay: defaultConsumerRetryDelay,
+		config:     consumerConfig,
+	}
+}
+
+// Run receives from all topics until ctx is done and the receivers are closed.
+func (consumer *AzureConsumer) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, eventType := range IdentityEvents {
+		wg.Add(1)
+
+		go func(topic string) {
+			defer wg.Done()
+			consumer.run(ctx, topic)
+		}(eventType)
+	}
+
+	wg.Wait()
+}
+
+func (consumer *AzureConsumer) run(ctx context.Context, topic string) {
+	for {
+		err := consumer.receive(ctx, topic)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		consumer.logger.Warning(ctx, "receiving identity events failed, retrying", "topic", topic, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(consumer.retryDelay):
+		}
+	}
+}
+
+func (consumer *AzureConsumer) receive(ctx context.Context, topic string) error {
+	r, err := consumer.newReceiver(topic)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), defaultConsumerRetryDelay)
+		defer cancel()
+
+		_ = r.Close(closeCtx)
+	}()
+
+	for {
+		messages, err := r.ReceiveMessages(ctx, consumer.config.Prefetch, nil)
+
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			consumer.handleMessage(ctx, r, topic, message)
+		}
+	}
+}
+
+func (consumer *AzureConsumer) handleMessage(ctx context.Context, r receiver, topic string, message *azservicebus.ReceivedMessage) {
+	ctx = tracing.Extract(ctx, message.ApplicationProperties)
+
+	eventType := messageEventType(topic, message)
+	err := consumer.handler.Handle(ctx, eventType, messageContentType(message), message.Body)
+
+	var poisonErr *PoisonMessageError
+
+	switch {
+	case err == nil:
+		err = r.CompleteMessage(ctx, message, nil)
+	case errors.As(err, &poisonErr):
+		err = consumer.reject(ctx, r, message, poisonErr.Reason, err)
+	case int(message.DeliveryCount) >= consumer.config.MaxDeliveries:
+		err = consumer.reject(ctx, r, message, reasonMaxDeliveries, err)
+	default:
+		consumer.logger.Warning(ctx, "handling identity event failed, abandoning", "type", eventType, "message_id", message.MessageID, "error", err)
+		err = r.AbandonMessage(ctx, message, nil)
+	}
+
+	if err != nil {
+		consumer.logger.Error(ctx, "settling identity event failed", "message_id", message.MessageID, "error", err)
+	}
+}
+
+func (consumer *AzureConsumer) reject(ctx context.Context, r receiver, message *azservicebus.ReceivedMessage, reason string, cause error) error {
+	consumer.logger.Error(ctx, "dead-lettering identity event", "message_id", message.MessageID, "reason", reason, "attempts", message.DeliveryCount, "error", cause)
+
+	description := cause.Error()
+
+	return r.DeadLetterMessage(ctx, message, &azservicebus.DeadLetterOptions{
+		Reason:           &reason,
+		ErrorDescription: &description,
+		PropertiesToModify: map[string]interface{}{
+			headerAttempts: int64(message.DeliveryCount),
+			headerFailedAt: time.Now().UTC(),
+		},
+	})
+}
+
+// messageEventType is the cloudEvents:type property, or else the topic.
+func messageEventType(topic string, message *azservicebus.ReceivedMessage) string {
+	if value, ok := message.ApplicationProperties[cloudevents.HeaderPrefix+"type"].(string); ok {
+		return value
+	}
+
+	return topic
+}
+
+func messageContentType(message *azservicebus.ReceivedMessage) string {
+	if message.ContentType == nil {
+		return ""
+	}
+
+	return *message.ContentType
+}
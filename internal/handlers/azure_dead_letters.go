@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/pkg/azure"
+	"user-service/pkg/tracing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+)
+
+// AzureDeadLetters is the dead letter queue of the subscriptions of the Azure
+// consumer. Replaying handles a message again, it is not sent to the topic
+// because that would deliver it to every subscription.
+type AzureDeadLetters struct {
+	serviceBus   *azure.ServiceBus
+	handler      *IdentityEventHandler
+	subscription string
+}
+
+func NewAzureDeadLetters(serviceBus *azure.ServiceBus, handler *IdentityEventHandler, cfg *config.Config) *AzureDeadLetters {
+	return &AzureDeadLetters{
+		serviceBus:   serviceBus,
+		handler:      handler,
+		subscription: consumerConfig(cfg).Subscription,
+	}
+}
+
+func (deadLetters *AzureDeadLetters) List(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	result := make([]domain.DeadLetter, 0, limit)
+
+	for _, topic := range IdentityEvents {
+		if len(result) == limit {
+			break
+		}
+
+		messages, err := deadLetters.serviceBus.PeekDeadLetters(ctx, topic, deadLetters.subscription, limit-len(result))
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, message := range messages {
+			result = append(result, newDeadLetterFromMessage(topic, message))
+		}
+	}
+
+	return result, nil
+}
+
+func (deadLetters *AzureDeadLetters) Get(ctx context.Context, id string) (domain.DeadLetter, error) {
+	topic, message, err := deadLetters.find(ctx, id)
+
+	if err != nil {
+		return domain.DeadLetter{}, err
+	}
+
+	return newDeadLetterFromMessage(topic, message), nil
+}
+
+func (deadLetters *AzureDeadLetters) Replay(ctx context.Context, id string) error {
+	return deadLetters.take(ctx, id, func(topic string, message *azservicebus.ReceivedMessage) error {
+		return deadLetters.handler.Handle(tracing.Extract(ctx, message.ApplicationProperties), messageEventType(topic, message), messageContentType(message), message.Body)
+	})
+}
+
+func (deadLetters *AzureDeadLetters) Delete(ctx context.Context, id string) error {
+	return deadLetters.take(ctx, id, func(topic string, message *azservicebus.ReceivedMessage) error {
+		return nil
+	})
+}
+
+func (deadLetters *AzureDeadLetters) Purge(ctx context.Context) (int, error) {
+	purged := 0
+
+	for _, topic := range IdentityEvents {
+		n, err := deadLetters.serviceBus.PurgeDeadLetters(ctx, topic, deadLetters.subscription)
+		purged += n
+
+		if err != nil {
+			return purged, err
+		}
+	}
+
+	return purged, nil
+}
+
+// find peeks the dead letter queues of all topics for the message.
+func (deadLetters *AzureDeadLetters) find(ctx context.Context, id string) (string, *azservicebus.ReceivedMessage, error) {
+	for _, topic := range IdentityEvents {
+		message, err := deadLetters.serviceBus.FindDeadLetter(ctx, topic, deadLetters.subscription, id)
+
+		if errors.Is(err, azure.ErrDeadLetterNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		return topic, message, nil
+	}
+
+	return "", nil, domain.NewNotFoundError("dead letter", id)
+}
+
+func (deadLetters *AzureDeadLetters) take(ctx context.Context, id string, fn func(topic string, message *azservicebus.ReceivedMessage) error) error {
+	topic, _, err := deadLetters.find(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	err = deadLetters.serviceBus.TakeDeadLetter(ctx, topic, deadLetters.subscription, id, func(message *azservicebus.ReceivedMessage) error {
+		return fn(topic, message)
+	})
+
+	return translateDeadLetterError(err, id)
+}
+
+func newDeadLetterFromMessage(topic string, message *azservicebus.ReceivedMessage) domain.DeadLetter {
+	deadLetter := domain.DeadLetter{
+		ID:          message.MessageID,
+		Type:        messageEventType(topic, message),
+		Attempts:    headerInt(message.ApplicationProperties, headerAttempts),
+		FailedAt:    headerTime(message.ApplicationProperties, headerFailedAt),
+		ContentType: messageContentType(message),
+		Body:        message.Body,
+	}
+
+	if message.DeadLetterReason != nil {
+		deadLetter.Reason = *message.DeadLetterReason
+	}
+
+	if message.DeadLetterErrorDescription != nil {
+		deadLetter.Description = *message.DeadLetterErrorDescription
+	}
+
+	if deadLetter.Attempts == 0 {
+		deadLetter.Attempts = int(message.DeliveryCount)
+	}
+
+	return deadLetter
+}
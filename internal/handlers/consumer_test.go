@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/internal/mock"
+	"user-service/pkg/logging"
+)
+
+type fakeAcknowledger struct {
+	acked   bool
+	nacked  bool
+	requeue bool
+}
+
+func (ack *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	ack.acked = true
+	return nil
+}
+
+func (ack *fakeAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	ack.nacked = true
+	ack.requeue = requeue
+	return nil
+}
+
+func (ack *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	return ack.Nack(tag, false, requeue)
+}
+
+type fakeReceiver struct {
+	completed    bool
+	abandoned    bool
+	deadLettered *azservicebus.DeadLetterOptions
+}
+
+func (r *fakeReceiver) ReceiveMessages(ctx context.Context, maxMessages int, options *azservicebus.ReceiveMessagesOptions) ([]*azservicebus.ReceivedMessage, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (r *fakeReceiver) CompleteMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.CompleteMessageOptions) error {
+	r.completed = true
+	return nil
+}
+
+func (r *fakeReceiver) AbandonMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.AbandonMessageOptions) error {
+	r.abandoned = true
+	return nil
+}
+
+func (r *fakeReceiver) DeadLetterMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.DeadLetterOptions) error {
+	r.deadLettered = options
+	return nil
+}
+
+func (r *fakeReceiver) Close(ctx context.Context) error {
+	return nil
+}
+
+type deadLetter struct {
+	queue   string
+	headers amqp.Table
+}
+
+type ConsumerTestSuite struct {
+	suite.Suite
+	MockService      *mock.UserService
+	RabbitMQConsumer *RabbitMQConsumer
+	AzureConsumer    *AzureConsumer
+	deadLetters      []deadLetter
+	deadLetterErr    error
+}
+
+func (suite *ConsumerTestSuite) SetupSuite() {
+	mockService := new(mock.UserService)
+	handler := NewIdentityEventHandler(mockService, logging.MockLogger{})
+	cfg := &config.Config{}
+
+	suite.MockService = mockService
+	suite.RabbitMQConsumer = NewRabbitMQConsumer(nil, handler, logging.MockLogger{}, cfg)
+	suite.AzureConsumer = NewAzureConsumer(nil, handler, logging.MockLogger{}, cfg)
+}
+
+func (suite *ConsumerTestSuite) SetupTest() {
+	suite.MockService.ExpectedCalls = nil
+	suite.MockService.Calls = nil
+	suite.deadLetters = nil
+	suite.deadLetterErr = nil
+	suite.RabbitMQConsumer.deadLetter = func(ctx context.Context, queue string, delivery amqp.Delivery, headers amqp.Table) error {
+		suite.deadLetters = append(suite.deadLetters, deadLetter{queue: queue, headers: headers})
+		return suite.deadLetterErr
+	}
+}
+
+func (suite *ConsumerTestSuite) delivery(routingKey, body string) (amqp.Delivery, *fakeAcknowledger) {
+	ack := &fakeAcknowledger{}
+
+	return amqp.Delivery{Acknowledger: ack, RoutingKey: routingKey, Body: []byte(body)}, ack
+}
+
+func (suite *ConsumerTestSuite) TestRabbitMQConsumer_Ack() {
+	suite.MockService.On("Delete", "test-id").Return(nil)
+
+	delivery, ack := suite.delivery(IdentityUserDeleted, `{"id":"test-id"}`)
+	suite.RabbitMQConsumer.handleDelivery(context.Background(), delivery)
+
+	suite.True(ack.acked)
+	suite.False(ack.nacked)
+}
+
+func (suite *ConsumerTestSuite) TestRabbitMQConsumer_TypeHeader() {
+	suite.MockService.On("Delete", "test-id").Return(nil)
+
+	delivery, ack := suite.delivery("other.key", `{"id":"test-id"}`)
+	delivery.Headers = amqp.Table{"cloudEvents:type": IdentityUserDeleted}
+	suite.RabbitMQConsumer.handleDelivery(context.Background(), delivery)
+
+	suite.True(ack.acked)
+}
+
+func (suite *ConsumerTestSuite) TestRabbitMQConsumer_Poison() {
+	delivery, ack := suite.delivery(IdentityUserDeleted, `not json`)
+	suite.RabbitMQConsumer.handleDelivery(context.Background(), delivery)
+
+	suite.True(ack.acked)
+	suite.False(ack.nacked)
+	suite.Require().Len(suite.deadLetters, 1)
+	suite.Equal("user-service.identity", suite.deadLetters[0].queue)
+	suite.Equal("malformed body", suite.deadLetters[0].headers[headerFailureReason])
+	suite.EqualValues(1, suite.deadLetters[0].headers[headerAttempts])
+	suite.Contains(suite.deadLetters[0].headers, headerFailedAt)
+}
+
+func (suite *ConsumerTestSuite) TestRabbitMQConsumer_Poison_DeadLetterFailed() {
+	suite.deadLetterErr = errors.New("publish failed")
+
+	delivery, ack := suite.delivery(IdentityUserDeleted, `not json`)
+	suite.RabbitMQConsumer.handleDelivery(context.Background(), delivery)
+
+	suite.False(ack.acked)
+	suite.True(ack.nacked)
+	suite.False(ack.requeue)
+}
+
+func (suite *ConsumerTestSuite) TestRabbitMQConsumer_MaxDeliveries() {
+	suite.MockService.On("Delete", "test-id").Return(errors.New("deleting user failed"))
+
+	delivery, ack := suite.delivery(IdentityUserDeleted, `{"id":"test-id"}`)
+	delivery.Headers = amqp.Table{"x-delivery-count": int64(4)}
+	suite.RabbitMQConsumer.handleDelivery(context.Background(), delivery)
+
+	suite.True(ack.acked)
+	suite.Require().Len(suite.deadLetters, 1)
+	suite.Equal(reasonMaxDeliveries, suite.deadLetters[0].headers[headerFailureReason])
+	suite.Equal("deleting user failed", suite.deadLetters[0].headers[headerFailureDescription])
+	suite.EqualValues(5, suite.deadLetters[0].headers[headerAttempts])
+}
+
+func (suite *ConsumerTestSuite) TestRabbitMQConsumer_Requeue() {
+	suite.MockService.On("Delete", "test-id").Return(errors.New("deleting user failed"))
+
+	delivery, ack := suite.delivery(IdentityUserDeleted, `{"id":"test-id"}`)
+	suite.RabbitMQConsumer.handleDelivery(context.Background(), delivery)
+
+	suite.True(ack.nacked)
+	suite.True(ack.requeue)
+	suite.Empty(suite.deadLetters)
+}
+
+func (suite *ConsumerTestSuite) TestAzureConsumer_Complete() {
+	suite.MockService.On("Delete", "test-id").Return(domain.NewNotFoundError("user", "test-id"))
+
+	r := &fakeReceiver{}
+	suite.AzureConsumer.handleMessage(context.Background(), r, IdentityUserDeleted, &azservicebus.ReceivedMessage{Body: []byte(`{"id":"test-id"}`)})
+
+	suite.True(r.completed)
+}
+
+func (suite *ConsumerTestSuite) TestAzureConsumer_DeadLetter() {
+	r := &fakeReceiver{}
+	suite.AzureConsumer.handleMessage(context.Background(), r, IdentityUserDeleted, &azservicebus.ReceivedMessage{Body: []byte(`{}`)})
+
+	suite.False(r.completed)
+	suite.Require().NotNil(r.deadLettered)
+	suite.Equal("invalid user", *r.deadLettered.Reason)
+}
+
+func (suite *ConsumerTestSuite) TestAzureConsumer_Abandon() {
+	suite.MockService.On("Delete", "test-id").Return(errors.New("deleting user failed"))
+
+	r := &fakeReceiver{}
+	suite.AzureConsumer.handleMessage(context.Background(), r, IdentityUserDeleted, &azservicebus.ReceivedMessage{Body: []byte(`{"id":"test-id"}`)})
+
+	suite.True(r.abandoned)
+	suite.Nil(r.deadLettered)
+}
+
+func (suite *ConsumerTestSuite) TestAzureConsumer_MaxDeliveries() {
+	suite.MockService.On("Delete", "test-id").Return(errors.New("deleting user failed"))
+
+	r := &fakeReceiver{}
+	suite.AzureConsumer.handleMessage(context.Background(), r, IdentityUserDeleted, &azservicebus.ReceivedMessage{Body: []byte(`{"id":"test-id"}`), DeliveryCount: 5})
+
+	suite.False(r.abandoned)
+	suite.Require().NotNil(r.deadLettered)
+	suite.Equal(reasonMaxDeliveries, *r.deadLettered.Reason)
+	suite.EqualValues(5, r.deadLettered.PropertiesToModify[headerAttempts])
+}
+
+func (suite *ConsumerTestSuite) TestAzureConsumer_Run_StopsOnCancel() {
+	consumer := *suite.AzureConsumer
+	consumer.newReceiver = func(topic string) (receiver, error) {
+		return &fakeReceiver{}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	consumer.Run(ctx)
+}
+
+func (suite *ConsumerTestSuite) TestNewDeadLetterFromDelivery() {
+	deadLetter := newDeadLetterFromDelivery(amqp.Delivery{
+		MessageId:  "message-id",
+		RoutingKey: IdentityUserDeleted,
+		Headers: amqp.Table{
+			headerFailureReason:      "invalid user",
+			headerFailureDescription: "invalid user: id: id is required",
+			headerAttempts:           int64(2),
+		},
+	})
+
+	suite.Equal("message-id", deadLetter.ID)
+	suite.Equal(IdentityUserDeleted, deadLetter.Type)
+	suite.Equal("invalid user", deadLetter.Reason)
+	suite.Equal(2, deadLetter.Attempts)
+}
+
+func (suite *ConsumerTestSuite) TestNewDeadLetterFromDelivery_RejectedByBroker() {
+	deadLetter := newDeadLetterFromDelivery(amqp.Delivery{
+		Headers: amqp.Table{"x-death": []interface{}{amqp.Table{"reason": "delivery_limit"}}},
+	})
+
+	suite.Equal("delivery_limit", deadLetter.Reason)
+}
+
+func TestUnit_ConsumerTestSuite(t *testing.T) {
+	suite.Run(t, new(ConsumerTestSuite))
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+	"user-service/pkg/authorization"
+	"user-service/pkg/azure"
+	"user-service/pkg/dto"
+	"user-service/pkg/rabbitmq"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers, or application properties, added to dead-lettered messages.
+const (
+	headerFailureReason      = "x-failure-reason"
+	headerFailureDescription = "x-failure-description"
+	headerAttempts           = "x-attempts"
+	headerFailedAt           = "x-failed-at"
+)
+
+const (
+	reasonMaxDeliveries = "max deliveries exceeded"
+
+	defaultDeadLetterLimit = 20
+	maxDeadLetterLimit     = 100
+)
+
+// deadLetterEndpoints serves the dead letters of one queue.
+type deadLetterEndpoints struct {
+	deadLetters interfaces.DeadLetterQueue
+}
+
+// SetupDeadLetterEndpoints exposes the dead letter queue of the consumer to admins.
+func (handler *HTTPHandler) SetupDeadLetterEndpoints(deadLetters interfaces.DeadLetterQueue) {
+	handler.setupDeadLetterEndpoints("/api/dead-letters", deadLetters)
+}
+
+// SetupOutboxDeadLetterEndpoints exposes the published messages the outbox gave
+// up on to admins.
+func (handler *HTTPHandler) SetupOutboxDeadLetterEndpoints(deadLetters interfaces.DeadLetterQueue) {
+	handler.setupDeadLetterEndpoints("/api/outbox/dead-letters", deadLetters)
+}
+
+func (handler *HTTPHandler) setupDeadLetterEndpoints(path string, deadLetters interfaces.DeadLetterQueue) {
+	endpoints := &deadLetterEndpoints{deadLetters: deadLetters}
+
+	api := handler.router.Group(path, handler.HandleErrors)
+	api.GET("", endpoints.GetDeadLetters)
+	api.DELETE("", endpoints.PurgeDeadLetters)
+	api.GET("/:id", endpoints.GetDeadLetter)
+	api.DELETE("/:id", endpoints.DeleteDeadLetter)
+	api.POST("/:id/replay", endpoints.ReplayDeadLetter)
+}
+
+// GetDeadLetters godoc
+// @Summary  list dead letters
+// @Schemes
+// @Description  lists consumed messages that could not be handled, or published messages the outbox gave up on
+// @Param        limit  query  int  false  "Maximum number of messages, at most 100"
+// @Produce      json
+// @Success      200  {array}  dto.DeadLetterResponse
+// @Router       /api/dead-letters [get]
+// @Router       /api/outbox/dead-letters [get]
+func (endpoints *deadLetterEndpoints) GetDeadLetters(c *gin.Context) {
+	if authorization.NewRest(c).AuthorizeAdmin() {
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultDeadLetterLimit)))
+
+		if err != nil || limit < 1 || limit > maxDeadLetterLimit {
+			abortWithError(c, domain.NewValidationError("limit", "limit must be a number between 1 and 100"))
+			return
+		}
+
+		deadLetters, err := endpoints.deadLetters.List(c.Request.Context(), limit)
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.CreateDeadLetterListResponse(deadLetters))
+		return
+	}
+
+	abortWithError(c, errAdminRequired)
+}
+
+// GetDeadLetter godoc
+// @Summary  get dead letter
+// @Schemes
+// @Param        id  path  string  true  "Message id"
+// @Description  gets a consumed message that could not be handled, or a published message the outbox gave up on
+// @Produce      json
+// @Success      200  {object}  dto.DeadLetterResponse
+// @Router       /api/dead-letters/{id} [get]
+// @Router       /api/outbox/dead-letters/{id} [get]
+func (endpoints *deadLetterEndpoints) GetDeadLetter(c *gin.Context) {
+	if authorization.NewRest(c).AuthorizeAdmin() {
+
+		deadLetter, err := endpoints.deadLetters.Get(c.Request.Context(), c.Param("id"))
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.CreateDeadLetterResponse(deadLetter))
+		return
+	}
+
+	abortWithError(c, errAdminRequired)
+}
+
+// ReplayDeadLetter godoc
+// @Summary  replay dead letter
+// @Schemes
+// @Param        id  path  string  true  "Message id"
+// @Description  handles the message again and removes it from the dead letter queue when it succeeds, or hands a published message back to the outbox
+// @Success      204
+// @Router       /api/dead-letters/{id}/replay [post]
+// @Router       /api/outbox/dead-letters/{id}/replay [post]
+func (endpoints *deadLetterEndpoints) ReplayDeadLetter(c *gin.Context) {
+	if authorization.NewRest(c).AuthorizeAdmin() {
+
+		err := endpoints.deadLetters.Replay(c.Request.Context(), c.Param("id"))
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	abortWithError(c, errAdminRequired)
+}
+
+// DeleteDeadLetter godoc
+// @Summary  delete dead letter
+// @Schemes
+// @Param        id  path  string  true  "Message id"
+// @Description  removes the message from the dead letter queue without handling it
+// @Success      204
+// @Router       /api/dead-letters/{id} [delete]
+// @Router       /api/outbox/dead-letters/{id} [delete]
+func (endpoints *deadLetterEndpoints) DeleteDeadLetter(c *gin.Context) {
+	if authorization.NewRest(c).AuthorizeAdmin() {
+
+		err := endpoints.deadLetters.Delete(c.Request.Context(), c.Param("id"))
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	abortWithError(c, errAdminRequired)
+}
+
+// PurgeDeadLetters godoc
+// @Summary  purge dead letters
+// @Schemes
+// @Description  removes every message from the dead letter queue
+// @Produce      json
+// @Success      200  {object}  dto.DeadLetterPurgeResponse
+// @Router       /api/dead-letters [delete]
+// @Router       /api/outbox/dead-letters [delete]
+func (endpoints *deadLetterEndpoints) PurgeDeadLetters(c *gin.Context) {
+	if authorization.NewRest(c).AuthorizeAdmin() {
+
+		purged, err := endpoints.deadLetters.Purge(c.Request.Context())
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.DeadLetterPurgeResponse{Purged: purged})
+		return
+	}
+
+	abortWithError(c, errAdminRequired)
+}
+
+func translateDeadLetterError(err error, id string) error {
+	if errors.Is(err, rabbitmq.ErrDeadLetterNotFound) || errors.Is(err, azure.ErrDeadLetterNotFound) {
+		return domain.NewNotFoundError("dead letter", id)
+	}
+
+	return err
+}
+
+func headerString(headers map[string]interface{}, key string) string {
+	value, _ := headers[key].(string)
+	return value
+}
+
+func headerInt(headers map[string]interface{}, key string) int {
+	switch value := headers[key].(type) {
+	case int:
+		return value
+	case int32:
+		return int(value)
+	case int64:
+		return int(value)
+	default:
+		return 0
+	}
+}
+
+func headerTime(headers map[string]interface{}, key string) *time.Time {
+	value, ok := headers[key].(time.Time)
+
+	if !ok {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+	"user-service/pkg/authorization"
+	"user-service/pkg/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupDebugEndpoints exposes the events kept by the in-memory publisher to
+// admins. It is meant for local development and tests.
+func (handler *HTTPHandler) SetupDebugEndpoints(publishedEvents interfaces.PublishedEvents) {
+	handler.publishedEvents = publishedEvents
+
+	debug := handler.router.Group("/debug", handler.HandleErrors)
+	debug.GET("/events", handler.GetPublishedEvents)
+}
+
+// GetPublishedEvents godoc
+// @Summary  list published events
+// @Schemes
+// @Description  lists the most recent events of the in-memory publisher, oldest first
+// @Param        type     query  string  false  "Only events of this type"
+// @Param        subject  query  string  false  "Only events of this user"
+// @Produce      json
+// @Success      200  {array}  dto.PublishedEventResponse
+// @Router       /debug/events [get]
+func (handler *HTTPHandler) GetPublishedEvents(c *gin.Context) {
+	if authorization.NewRest(c).AuthorizeAdmin() {
+		eventType := c.Query("type")
+		subject := c.Query("subject")
+
+		var events []domain.PublishedEvent
+
+		for _, event := range handler.publishedEvents.Events(c.Request.Context()) {
+			if (eventType == "" || event.Type == eventType) && (subject == "" || event.Subject == subject) {
+				events = append(events, event)
+			}
+		}
+
+		c.JSON(http.StatusOK, dto.CreatePublishedEventListResponse(events))
+		return
+	}
+
+	abortWithError(c, errAdminRequired)
+}
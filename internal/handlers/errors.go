@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"user-service/internal/core/domain"
+	"user-service/pkg/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+const problemContentType = "application/problem+json"
+
+var (
+	errNotAllowed    = domain.NewForbiddenError("not allowed to access this user")
+	errAdminRequired = domain.NewForbiddenError("admin rights are required")
+	errInvalidBody   = domain.NewValidationError("body", "request body is not valid")
+)
+
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// HandleErrors renders the last error added to the context as an RFC 7807 problem response.
+func (handler *HTTPHandler) HandleErrors(c *gin.Context) {
+	c.Next()
+
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+
+	err := c.Errors.Last().Err
+	problem := createProblemResponse(err)
+	problem.Instance = c.Request.URL.Path
+
+	if problem.Status == http.StatusInternalServerError {
+		handler.logger.Error(c.Request.Context(), err.Error())
+	}
+
+	c.Header("Content-Type", problemContentType)
+	c.JSON(problem.Status, problem)
+}
+
+func createProblemResponse(err error) dto.ProblemResponse {
+	var validationErr *domain.ValidationError
+	var notFoundErr *domain.NotFoundError
+	var conflictErr *domain.ConflictError
+	var forbiddenErr *domain.ForbiddenError
+	var preconditionErr *domain.PreconditionFailedError
+
+	switch {
+	case errors.Is(err, ErrPoisonMessage):
+		return dto.ProblemResponse{
+			Type:   "/problems/unprocessable-message",
+			Title:  "Message cannot be handled",
+			Status: http.StatusUnprocessableEntity,
+			Detail: err.Error(),
+		}
+	case errors.As(err, &validationErr):
+		return dto.ProblemResponse{
+			Type:   "/problems/validation-error",
+			Title:  "Validation failed",
+			Status: http.StatusBadRequest,
+			Detail: validationErr.Error(),
+			Errors: []dto.FieldErrorResponse{{Field: validationErr.Field, Message: validationErr.Message}},
+		}
+	case errors.As(err, &notFoundErr):
+		return dto.ProblemResponse{
+			Type:   "/problems/not-found",
+			Title:  "Not found",
+			Status: http.StatusNotFound,
+			Detail: notFoundErr.Error(),
+		}
+	case errors.As(err, &conflictErr):
+		problem := dto.ProblemResponse{
+			Type:   "/problems/conflict",
+			Title:  "Conflict",
+			Status: http.StatusConflict,
+			Detail: conflictErr.Error(),
+		}
+
+		if conflictErr.Field != "" {
+			problem.Errors = []dto.FieldErrorResponse{{Field: conflictErr.Field, Message: conflictErr.Message}}
+		}
+
+		return problem
+	case errors.As(err, &forbiddenErr):
+		return dto.ProblemResponse{
+			Type:   "/problems/forbidden",
+			Title:  "Forbidden",
+			Status: http.StatusForbidden,
+			Detail: forbiddenErr.Error(),
+		}
+	case errors.As(err, &preconditionErr):
+		return dto.ProblemResponse{
+			Type:   "/problems/precondition-failed",
+			Title:  "Precondition failed",
+			Status: http.StatusPreconditionFailed,
+			Detail: preconditionErr.Error(),
+		}
+	case errors.Is(err, dto.ErrUnsupportedPatch):
+		return dto.ProblemResponse{
+			Type:   "/problems/unsupported-media-type",
+			Title:  "Unsupported media type",
+			Status: http.StatusUnsupportedMediaType,
+			Detail: "use " + dto.MergePatchContentType + " or " + dto.JSONPatchContentType,
+		}
+	default:
+		return dto.ProblemResponse{
+			Type:   "about:blank",
+			Title:  "Internal server error",
+			Status: http.StatusInternalServerError,
+		}
+	}
+}
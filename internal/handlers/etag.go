@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"user-service/internal/core/domain"
+)
+
+func formatETag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// parseIfMatch returns the version requested by an If-Match header.
+// A missing header or a wildcard results in version 0, which skips the check.
+func parseIfMatch(header string) (int64, error) {
+	header = strings.TrimSpace(header)
+
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(header, "W/"), `"`), 10, 64)
+
+	if err != nil || version < 1 {
+		return 0, domain.NewPreconditionFailedError("If-Match does not match the current version")
+	}
+
+	return version, nil
+}
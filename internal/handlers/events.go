@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"user-service/internal/core/domain"
+	"user-service/pkg/dto"
+	"user-service/pkg/events"
+
+	"github.com/gin-gonic/gin"
+)
+
+const schemaContentType = "application/schema+json"
+
+// GetEventSchemas godoc
+// @Summary  list event schemas
+// @Schemes
+// @Description  lists the JSON schemas of every version of the published event payloads
+// @Produce      json
+// @Success      200  {array}  dto.EventSchemaResponse
+// @Router       /api/events/schemas [get]
+func (handler *HTTPHandler) GetEventSchemas(c *gin.Context) {
+	baseURL := handler.schemaBaseURL(c)
+	response := make([]dto.EventSchemaResponse, 0, len(events.Definitions))
+
+	for _, definition := range events.Definitions {
+		response = append(response, dto.CreateEventSchemaResponse(definition, baseURL))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetEventSchema godoc
+// @Summary  get event schema
+// @Schemes
+// @Param        type     path  string  true  "Event type, e.g. bikepack.user.created"
+// @Param        version  path  int     true  "Payload version"
+// @Description  gets the JSON schema of one version of an event payload
+// @Produce      application/schema+json
+// @Success      200  {object}  object
+// @Router       /api/events/schemas/{type}/{version} [get]
+func (handler *HTTPHandler) GetEventSchema(c *gin.Context) {
+	id := fmt.Sprintf("%s/%s", c.Param("type"), c.Param("version"))
+	version, err := strconv.Atoi(c.Param("version"))
+
+	if err != nil {
+		abortWithError(c, domain.NewNotFoundError("event schema", id))
+		return
+	}
+
+	definition, ok := events.Find(c.Param("type"), version)
+
+	if !ok {
+		abortWithError(c, domain.NewNotFoundError("event schema", id))
+		return
+	}
+
+	c.Header("Content-Type", schemaContentType)
+	c.JSON(http.StatusOK, definition.Schema(handler.schemaBaseURL(c)))
+}
+
+func (handler *HTTPHandler) schemaBaseURL(c *gin.Context) string {
+	if handler.config.Events.SchemaURL != "" {
+		return handler.config.Events.SchemaURL
+	}
+
+	scheme := "http"
+
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	return fmt.Sprintf("%s://%s/api/events/schemas", scheme, c.Request.Host)
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+	"user-service/pkg/cloudevents"
+	"user-service/pkg/dto"
+	"user-service/pkg/logging"
+)
+
+const (
+	IdentityUserRegistered = "auth.user.registered"
+	IdentityUserDeleted    = "auth.user.deleted"
+)
+
+const (
+	defaultConsumerQueue         = "user-service.identity"
+	defaultConsumerSubscription  = "user-service"
+	defaultConsumerPrefetch      = 10
+	defaultConsumerMaxDeliveries = 5
+	defaultConsumerRetryDelay    = time.Second
+)
+
+// IdentityEvents are the event types consumed from the identity provider.
+var IdentityEvents = []string{IdentityUserRegistered, IdentityUserDeleted}
+
+var ErrPoisonMessage = errors.New("poison message")
+
+// PoisonMessageError is returned for messages that can never be handled,
+// they are dead-lettered instead of retried.
+type PoisonMessageError struct {
+	Reason string
+	Err    error
+}
+
+func newPoisonMessageError(reason string, err error) *PoisonMessageError {
+	return &PoisonMessageError{Reason: reason, Err: err}
+}
+
+func (err *PoisonMessageError) Error() string {
+	if err.Err == nil {
+		return err.Reason
+	}
+
+	return fmt.Sprintf("%s: %v", err.Reason, err.Err)
+}
+
+func (err *PoisonMessageError) Unwrap() error {
+	return err.Err
+}
+
+func (err *PoisonMessageError) Is(target error) bool {
+	return target == ErrPoisonMessage
+}
+
+// IdentityEventHandler provisions and removes users for the events of the
+// identity provider. Handling is idempotent on the user id, so redelivered
+// messages are acknowledged without changing anything.
+type IdentityEventHandler struct {
+	userService interfaces.UserService
+	logger      logging.Logger
+}
+
+func NewIdentityEventHandler(userService interfaces.UserService, logger logging.Logger) *IdentityEventHandler {
+	return &IdentityEventHandler{
+		userService: userService,
+		logger:      logger,
+	}
+}
+
+// Handle handles a message of eventType. Structured CloudEvents are unwrapped,
+// their type takes precedence over eventType.
+func (handler *IdentityEventHandler) Handle(ctx context.Context, eventType, contentType string, body []byte) error {
+	if contentType == cloudevents.StructuredContentType {
+		var event cloudevents.Event
+
+		if err := json.Unmarshal(body, &event); err != nil {
+			return newPoisonMessageError("malformed cloudevent", err)
+		}
+
+		eventType = event.Type
+		body = event.Data
+	}
+
+	switch eventType {
+	case IdentityUserRegistered:
+		return handler.userRegistered(ctx, body)
+	case IdentityUserDeleted:
+		return handler.userDeleted(ctx, body)
+	default:
+		return newPoisonMessageError(fmt.Sprintf("unknown event type %q", eventType), nil)
+	}
+}
+
+func (handler *IdentityEventHandler) userRegistered(ctx context.Context, body []byte) error {
+	var event dto.IdentityUserRegistered
+
+	if err := json.Unmarshal(body, &event); err != nil {
+		return newPoisonMessageError("malformed body", err)
+	}
+
+	_, err := handler.userService.Create(ctx, event.ID, event.Name, event.LastName, event.Email)
+
+	var conflictErr *domain.ConflictError
+
+	// A conflict on the id means the user was provisioned before.
+	if errors.As(err, &conflictErr) && conflictErr.Field == "id" {
+		handler.logger.Info(ctx, "user already provisioned", "id", event.ID)
+		return nil
+	}
+
+	if errors.Is(err, domain.ErrValidation) || errors.Is(err, domain.ErrConflict) {
+		return newPoisonMessageError("invalid user", err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	handler.logger.Info(ctx, "user provisioned", "id", event.ID)
+
+	return nil
+}
+
+func (handler *IdentityEventHandler) userDeleted(ctx context.Context, body []byte) error {
+	var event dto.IdentityUserDeleted
+
+	if err := json.Unmarshal(body, &event); err != nil {
+		return newPoisonMessageError("malformed body", err)
+	}
+
+	if event.ID == "" {
+		return newPoisonMessageError("invalid user", domain.NewValidationError("id", "id is required"))
+	}
+
+	err := handler.userService.Delete(ctx, event.ID)
+
+	if errors.Is(err, domain.ErrNotFound) {
+		handler.logger.Info(ctx, "user already deleted", "id", event.ID)
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	handler.logger.Info(ctx, "user deleted", "id", event.ID)
+
+	return nil
+}
+
+func consumerConfig(cfg *config.Config) config.Consumer {
+	consumer := cfg.Consumer
+
+	if consumer.Queue == "" {
+		consumer.Queue = defaultConsumerQueue
+	}
+
+	if consumer.Subscription == "" {
+		consumer.Subscription = defaultConsumerSubscription
+	}
+
+	if consumer.Prefetch <= 0 {
+		consumer.Prefetch = defaultConsumerPrefetch
+	}
+
+	if consumer.MaxDeliveries <= 0 {
+		consumer.MaxDeliveries = defaultConsumerMaxDeliveries
+	}
+
+	return consumer
+}
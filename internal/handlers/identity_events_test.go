@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+	"user-service/internal/core/domain"
+	"user-service/internal/mock"
+	"user-service/pkg/cloudevents"
+	"user-service/pkg/dto"
+	"user-service/pkg/logging"
+)
+
+type IdentityEventHandlerTestSuite struct {
+	suite.Suite
+	MockService *mock.UserService
+	TestHandler *IdentityEventHandler
+	TestData    struct {
+		Registered dto.IdentityUserRegistered
+		User       domain.User
+	}
+}
+
+func (suite *IdentityEventHandlerTestSuite) SetupSuite() {
+	mockService := new(mock.UserService)
+
+	suite.MockService = mockService
+	suite.TestHandler = NewIdentityEventHandler(mockService, logging.MockLogger{})
+	suite.TestData.Registered = dto.IdentityUserRegistered{
+		ID:       "test-id",
+		Name:     "test",
+		LastName: "user",
+		Email:    "test@email.com",
+	}
+	suite.TestData.User = domain.User{
+		ID:       "test-id",
+		Name:     "test",
+		LastName: "user",
+		Email:    "test@email.com",
+	}
+}
+
+func (suite *IdentityEventHandlerTestSuite) SetupTest() {
+	suite.MockService.ExpectedCalls = nil
+	suite.MockService.Calls = nil
+}
+
+func (suite *IdentityEventHandlerTestSuite) registeredBody() []byte {
+	body, err := json.Marshal(suite.TestData.Registered)
+	suite.NoError(err)
+
+	return body
+}
+
+func (suite *IdentityEventHandlerTestSuite) expectCreate(err error) {
+	registered := suite.TestData.Registered
+	suite.MockService.On("Create", registered.ID, registered.Name, registered.LastName, registered.Email).Return(suite.TestData.User, err)
+}
+
+func (suite *IdentityEventHandlerTestSuite) TestIdentityEventHandler_Registered() {
+	suite.expectCreate(nil)
+
+	err := suite.TestHandler.Handle(context.Background(), IdentityUserRegistered, cloudevents.JSONContentType, suite.registeredBody())
+
+	suite.NoError(err)
+	suite.MockService.AssertNumberOfCalls(suite.T(), "Create", 1)
+}
+
+func (suite *IdentityEventHandlerTestSuite) TestIdentityEventHandler_Registered_Structured() {
+	suite.expectCreate(nil)
+
+	event, err := cloudevents.NewJSONEvent("event-id", "/auth", IdentityUserRegistered, suite.TestData.Registered.ID, time.Now(), suite.TestData.Registered)
+	suite.NoError(err)
+
+	body, err := event.Structured()
+	suite.NoError(err)
+
+	err = suite.TestHandler.Handle(context.Background(), "", cloudevents.StructuredContentType, body)
+
+	suite.NoError(err)
+	suite.MockService.AssertNumberOfCalls(suite.T(), "Create", 1)
+}
+
+func (suite *IdentityEventHandlerTestSuite) TestIdentityEventHandler_Registered_AlreadyProvisioned() {
+	suite.expectCreate(domain.NewConflictError("id", "id is already in use"))
+
+	err := suite.TestHandler.Handle(context.Background(), IdentityUserRegistered, "", suite.registeredBody())
+
+	suite.NoError(err)
+}
+
+func (suite *IdentityEventHandlerTestSuite) TestIdentityEventHandler_Registered_EmailInUse() {
+	suite.expectCreate(domain.NewConflictError("email", "email is already in use"))
+
+	err := suite.TestHandler.Handle(context.Background(), IdentityUserRegistered, "", suite.registeredBody())
+
+	suite.ErrorIs(err, ErrPoisonMessage)
+	suite.ErrorIs(err, domain.ErrConflict)
+}
+
+func (suite *IdentityEventHandlerTestSuite) TestIdentityEventHandler_Registered_Invalid() {
+	suite.expectCreate(domain.NewValidationError("email", "email is not valid"))
+
+	err := suite.TestHandler.Handle(context.Background(), IdentityUserRegistered, "", suite.registeredBody())
+
+	suite.ErrorIs(err, ErrPoisonMessage)
+}
+
+func (suite *IdentityEventHandlerTestSuite) TestIdentityEventHandler_Registered_Malformed() {
+	err := suite.TestHandler.Handle(context.Background(), IdentityUserRegistered, "", []byte("{"))
+
+	suite.ErrorIs(err, ErrPoisonMessage)
+	suite.MockService.AssertNotCalled(suite.T(), "Create")
+}
+
+func (suite *IdentityEventHandlerTestSuite) TestIdentityEventHandler_Registered_Failed() {
+	suite.expectCreate(errors.New("saving new user failed"))
+
+	err := suite.TestHandler.Handle(context.Background(), IdentityUserRegistered, "", suite.registeredBody())
+
+	suite.Error(err)
+	suite.NotErrorIs(err, ErrPoisonMessage)
+}
+
+func (suite *IdentityEventHandlerTestSuite) TestIdentityEventHandler_Deleted() {
+	suite.MockService.On("Delete", suite.TestData.User.ID).Return(nil)
+
+	err := suite.TestHandler.Handle(context.Background(), IdentityUserDeleted, "", []byte(`{"id":"test-id"}`))
+
+	suite.NoError(err)
+	suite.MockService.AssertCalled(suite.T(), "Delete", suite.TestData.User.ID)
+}
+
+func (suite *IdentityEventHandlerTestSuite) TestIdentityEventHandler_Deleted_AlreadyDeleted() {
+	suite.MockService.On("Delete", suite.TestData.User.ID).Return(domain.NewNotFoundError("user", suite.TestData.User.ID))
+
+	err := suite.TestHandler.Handle(context.Background(), IdentityUserDeleted, "", []byte(`{"id":"test-id"}`))
+
+	suite.NoError(err)
+}
+
+func (suite *IdentityEventHandlerTestSuite) TestIdentityEventHandler_Deleted_MissingID() {
+	err := suite.TestHandler.Handle(context.Background(), IdentityUserDeleted, "", []byte(`{}`))
+
+	suite.ErrorIs(err, ErrPoisonMessage)
+	suite.MockService.AssertNotCalled(suite.T(), "Delete")
+}
+
+func (suite *IdentityEventHandlerTestSuite) TestIdentityEventHandler_UnknownType() {
+	err := suite.TestHandler.Handle(context.Background(), "auth.user.unknown", "", []byte(`{}`))
+
+	suite.ErrorIs(err, ErrPoisonMessage)
+}
+
+func TestUnit_IdentityEventHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(IdentityEventHandlerTestSuite))
+}
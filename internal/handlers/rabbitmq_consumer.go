@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/config"
+	"user-service/pkg/cloudevents"
+	"user-service/pkg/logging"
+	"user-service/pkg/rabbitmq"
+	"user-service/pkg/tracing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var errDeliveriesClosed = errors.New("rabbitmq: deliveries channel closed")
+
+// RabbitMQConsumer consumes the identity events from a durable quorum queue
+// bound to the exchange. Messages are acknowledged once handled, poison
+// messages and messages that failed MaxDeliveries times are moved to
+// <queue>.dead-letter with the failure reason and attempt count in the headers.
+type RabbitMQConsumer struct {
+	rabbitmq   *rabbitmq.RabbitMQ
+	deadLetter func(ctx context.Context, queue string, delivery amqp.Delivery, headers amqp.Table) error
+	handler    *IdentityEventHandler
+	logger     logging.Logger
+	exchange   string
+	retryDelay time.Duration
+	config     config.Consumer
+}
+
+func NewRabbitMQConsumer(rmq *rabbitmq.RabbitMQ, handler *IdentityEventHandler, logger logging.Logger, cfg *config.Config) *RabbitMQConsumer {
+	retryDelay := cfg.RabbitMQ.ReconnectBackoff
+
+	if retryDelay <= 0 {
+		retryDelay = defaultConsumerRetryDelay
+	}
+
+	return &RabbitMQConsumer{
+		rabbitmq:   rmq,
+		deadLetter: rmq.DeadLetter,
+		handler:    handler,
+		logger:     logger,
+		exchange:   cfg.RabbitMQ.Exchange,
+		retryDelay: retryDelay,
+		config:     consumerConfig(cfg),
+	}
+}
+
+// Run consumes until ctx is done. When the channel is lost it opens a new one
+// once the connection has been restored.
+func (consumer *RabbitMQConsumer) Run(ctx context.Context) {
+	for {
+		err := consumer.consume(ctx)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		consumer.logger.Warning(ctx, "consuming identity events failed, retrying", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(consumer.retryDelay):
+		}
+	}
+}
+
+func (consumer *RabbitMQConsumer) consume(ctx context.Context) error {
+	channel, err := consumer.rabbitmq.OpenChannel()
+
+	if err != nil {
+		return err
+	}
+
+	defer channel.Close()
+
+	if err = consumer.declare(channel); err != nil {
+		return err
+	}
+
+	err = channel.Qos(consumer.config.Prefetch, 0, false)
+
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := channel.Consume(
+		consumer.config.Queue,
+		"",
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return errDeliveriesClosed
+			}
+
+			consumer.handleDelivery(ctx, delivery)
+		}
+	}
+}
+
+// declare declares the queue with its dead letter queue and binds it to the
+// identity events.
+func (consumer *RabbitMQConsumer) declare(channel *amqp.Channel) error {
+	err := rabbitmq.DeclareQueue(channel, consumer.config.Queue, consumer.config.MaxDeliveries)
+
+	if err != nil {
+		return err
+	}
+
+	for _, eventType := range IdentityEvents {
+		err = channel.QueueBind(consumer.config.Queue, eventType, consumer.exchange, false, nil)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (consumer *RabbitMQConsumer) handleDelivery(ctx context.Context, delivery amqp.Delivery) {
+	ctx = tracing.Extract(ctx, delivery.Headers)
+
+	eventType := deliveryEventType(delivery)
+	err := consumer.handler.Handle(ctx, eventType, delivery.ContentType, delivery.Body)
+
+	var poisonErr *PoisonMessageError
+
+	switch {
+	case err == nil:
+		err = delivery.Ack(false)
+	case errors.As(err, &poisonErr):
+		err = consumer.reject(ctx, delivery, poisonErr.Reason, err)
+	case rabbitmq.Attempts(delivery) >= consumer.config.MaxDeliveries:
+		err = consumer.reject(ctx, delivery, reasonMaxDeliveries, err)
+	default:
+		consumer.logger.Warning(ctx, "handling identity event failed, requeueing", "type", eventType, "message_id", delivery.MessageId, "error", err)
+		err = delivery.Nack(false, true)
+	}
+
+	if err != nil {
+		consumer.logger.Error(ctx, "settling identity event failed", "message_id", delivery.MessageId, "error", err)
+	}
+}
+
+// reject moves the delivery to the dead letter queue. When that fails the
+// broker dead-letters it instead, without the failure headers.
+func (consumer *RabbitMQConsumer) reject(ctx context.Context, delivery amqp.Delivery, reason string, cause error) error {
+	attempts := rabbitmq.Attempts(delivery)
+
+	consumer.logger.Error(ctx, "dead-lettering identity event", "message_id", delivery.MessageId, "reason", reason, "attempts", attempts, "error", cause)
+
+	err := consumer.deadLetter(ctx, consumer.config.Queue, delivery, amqp.Table{
+		headerFailureReason:      reason,
+		headerFailureDescription: cause.Error(),
+		headerAttempts:           int64(attempts),
+		headerFailedAt:           time.Now().UTC(),
+	})
+
+	if err != nil {
+		consumer.logger.Warning(ctx, "publishing dead letter failed, rejecting", "message_id", delivery.MessageId, "error", err)
+		return delivery.Nack(false, false)
+	}
+
+	return delivery.Ack(false)
+}
+
+// deliveryEventType is the cloudEvents:type header, or else the routing key.
+func deliveryEventType(delivery amqp.Delivery) string {
+	if value, ok := delivery.Headers[cloudevents.HeaderPrefix+"type"].(string); ok {
+		return value
+	}
+
+	return delivery.RoutingKey
+}
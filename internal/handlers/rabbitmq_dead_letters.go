@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"user-service/config"
+	"user-service/internal/core/domain"
+	"user-service/pkg/rabbitmq"
+	"user-service/pkg/tracing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// RabbitMQDeadLetters is the dead letter queue of the RabbitMQ consumer.
+// Replaying handles a message again, it is not published to other consumers.
+type RabbitMQDeadLetters struct {
+	rabbitmq *rabbitmq.RabbitMQ
+	handler  *IdentityEventHandler
+	queue    string
+}
+
+func NewRabbitMQDeadLetters(rmq *rabbitmq.RabbitMQ, handler *IdentityEventHandler, cfg *config.Config) *RabbitMQDeadLetters {
+	return &RabbitMQDeadLetters{
+		rabbitmq: rmq,
+		handler:  handler,
+		queue:    consumerConfig(cfg).Queue,
+	}
+}
+
+func (deadLetters *RabbitMQDeadLetters) List(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	deliveries, err := deadLetters.rabbitmq.PeekDeadLetters(deadLetters.queue, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.DeadLetter, 0, len(deliveries))
+
+	for _, delivery := range deliveries {
+		result = append(result, newDeadLetterFromDelivery(delivery))
+	}
+
+	return result, nil
+}
+
+func (deadLetters *RabbitMQDeadLetters) Get(ctx context.Context, id string) (domain.DeadLetter, error) {
+	delivery, err := deadLetters.rabbitmq.FindDeadLetter(deadLetters.queue, id)
+
+	if err != nil {
+		return domain.DeadLetter{}, translateDeadLetterError(err, id)
+	}
+
+	return newDeadLetterFromDelivery(delivery), nil
+}
+
+func (deadLetters *RabbitMQDeadLetters) Replay(ctx context.Context, id string) error {
+	err := deadLetters.rabbitmq.TakeDeadLetter(deadLetters.queue, id, func(delivery amqp.Delivery) error {
+		return deadLetters.handler.Handle(tracing.Extract(ctx, delivery.Headers), deliveryEventType(delivery), delivery.ContentType, delivery.Body)
+	})
+
+	return translateDeadLetterError(err, id)
+}
+
+func (deadLetters *RabbitMQDeadLetters) Delete(ctx context.Context, id string) error {
+	err := deadLetters.rabbitmq.TakeDeadLetter(deadLetters.queue, id, func(delivery amqp.Delivery) error {
+		return nil
+	})
+
+	return translateDeadLetterError(err, id)
+}
+
+func (deadLetters *RabbitMQDeadLetters) Purge(ctx context.Context) (int, error) {
+	return deadLetters.rabbitmq.PurgeDeadLetters(deadLetters.queue)
+}
+
+// newDeadLetterFromDelivery reads the failure headers. Messages dead-lettered by
+// the broker itself only have the reason in the x-death header.
+func newDeadLetterFromDelivery(delivery amqp.Delivery) domain.DeadLetter {
+	deadLetter := domain.DeadLetter{
+		ID:          delivery.MessageId,
+		Type:        deliveryEventType(delivery),
+		Reason:      headerString(delivery.Headers, headerFailureReason),
+		Description: headerString(delivery.Headers, headerFailureDescription),
+		Attempts:    headerInt(delivery.Headers, headerAttempts),
+		FailedAt:    headerTime(delivery.Headers, headerFailedAt),
+		ContentType: delivery.ContentType,
+		Body:        delivery.Body,
+	}
+
+	if deaths, ok := delivery.Headers["x-death"].([]interface{}); ok && len(deaths) > 0 && deadLetter.Reason == "" {
+		if death, ok := deaths[0].(amqp.Table); ok {
+			deadLetter.Reason = headerString(death, "reason")
+			deadLetter.FailedAt = headerTime(death, "time")
+		}
+	}
+
+	return deadLetter
+}
@@ -1,8 +1,14 @@
 package handlers
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"user-service/config"
+	"user-service/internal/core/domain"
 	"user-service/internal/core/interfaces"
 	"user-service/pkg/authorization"
 	"user-service/pkg/dto"
@@ -16,10 +22,11 @@ import (
 )
 
 type HTTPHandler struct {
-	userService interfaces.UserService
-	router      *gin.Engine
-	logger      logging.Logger
-	config      *config.Config
+	userService     interfaces.UserService
+	publishedEvents interfaces.PublishedEvents
+	router          *gin.Engine
+	logger          logging.Logger
+	config          *config.Config
 }
 
 func NewRest(userService interfaces.UserService, router *gin.Engine, logger logging.Logger, config *config.Config) *HTTPHandler {
@@ -32,11 +39,18 @@ func NewRest(userService interfaces.UserService, router *gin.Engine, logger logg
 }
 
 func (handler *HTTPHandler) SetupEndpoints() {
-	api := handler.router.Group("/api")
+	api := handler.router.Group("/api", handler.HandleErrors)
 	api.GET("/users", handler.GetAll)
+	api.GET("/users/search", handler.Search)
 	api.GET("/users/:id", handler.Get)
 	api.POST("/users", handler.Create)
 	api.PUT("/users/:id", handler.Update)
+	api.PATCH("/users/:id", handler.Patch)
+	api.DELETE("/users/:id", handler.Delete)
+	api.POST("/users/:id/erasure", handler.Erase)
+	api.GET("/users/:id/export", handler.Export)
+	api.GET("/events/schemas", handler.GetEventSchemas)
+	api.GET("/events/schemas/:type/:version", handler.GetEventSchema)
 }
 
 func (handler *HTTPHandler) SetupSwagger() {
@@ -55,10 +69,15 @@ func (handler *HTTPHandler) SetupHealthprobe() {
 // GetAll godoc
 // @Summary  get all users
 // @Schemes
-// @Description  gets all users in the system
+// @Description  gets a page of users in the system
+// @Param        limit        query  int     false  "Page size"
+// @Param        cursor       query  string  false  "Cursor of the next page"
+// @Param        sort         query  string  false  "name, last_name, email or created_at, prefix with - for descending"
+// @Param        email        query  string  false  "Filter on email"
+// @Param        name_prefix  query  string  false  "Filter on the start of the name"
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  dto.UserListResponse
+// @Success      200  {object}  dto.UserPageResponse
 // @Router       /api/users [get]
 func (handler *HTTPHandler) GetAll(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -67,18 +86,91 @@ func (handler *HTTPHandler) GetAll(c *gin.Context) {
 
 	if authorization.NewRest(c).AuthorizeAdmin() {
 
-		users, err := handler.userService.GetAll(ctx)
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
 
 		if err != nil {
-			c.AbortWithStatus(http.StatusNotFound)
+			abortWithError(c, domain.NewValidationError("limit", "limit must be a number"))
 			return
 		}
 
-		c.JSON(http.StatusOK, dto.CreateUserListResponse(users))
+		query, err := domain.NewUserQuery(limit, c.Query("cursor"), c.Query("sort"), c.Query("email"), c.Query("name_prefix"))
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		page, err := handler.userService.GetAll(ctx, query)
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.CreateUserPageResponse(page))
+		return
+	}
+
+	abortWithError(c, errAdminRequired)
+}
+
+// Search godoc
+// @Summary  search users
+// @Schemes
+// @Description  searches users by partial name, last name or email, best matches first
+// @Param        q     query  string  true   "Search query"
+// @Param        page  query  int     false  "Page number, starting at 1"
+// @Param        size  query  int     false  "Page size"
+// @Produce      json
+// @Success      200  {object}  dto.UserSearchResponse
+// @Router       /api/users/search [get]
+func (handler *HTTPHandler) Search(c *gin.Context) {
+	ctx := c.Request.Context()
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if authorization.NewRest(c).AuthorizeAdmin() {
+
+		query, err := domain.NewSearchQuery(c.Query("q"))
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		number, err := strconv.Atoi(c.DefaultQuery("page", "0"))
+
+		if err != nil {
+			abortWithError(c, domain.NewValidationError("page", "page must be a number"))
+			return
+		}
+
+		size, err := strconv.Atoi(c.DefaultQuery("size", "0"))
+
+		if err != nil {
+			abortWithError(c, domain.NewValidationError("size", "size must be a number"))
+			return
+		}
+
+		page, err := domain.NewSearchPage(number, size)
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		result, err := handler.userService.Search(ctx, query, page)
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.CreateUserSearchResponse(result, page))
 		return
 	}
 
-	c.AbortWithStatus(http.StatusUnauthorized)
+	abortWithError(c, errAdminRequired)
 }
 
 // Get godoc
@@ -101,15 +193,16 @@ func (handler *HTTPHandler) Get(c *gin.Context) {
 		user, err := handler.userService.Get(ctx, c.Param("id"))
 
 		if err != nil {
-			c.AbortWithStatus(http.StatusNotFound)
+			abortWithError(c, err)
 			return
 		}
 
+		c.Header("ETag", formatETag(user.Version))
 		c.JSON(http.StatusOK, dto.CreateUserResponse(user))
 		return
 	}
 
-	c.AbortWithStatus(http.StatusUnauthorized)
+	abortWithError(c, errNotAllowed)
 }
 
 // Create godoc
@@ -127,10 +220,15 @@ func (handler *HTTPHandler) Create(c *gin.Context) {
 	defer span.End()
 
 	body := dto.BodyCreateUser{}
-	err := c.BindJSON(&body)
+	err := c.ShouldBindJSON(&body)
+
+	if err != nil {
+		abortWithError(c, errInvalidBody)
+		return
+	}
 
-	if err != nil || body.ID == "" {
-		c.AbortWithStatus(http.StatusBadRequest)
+	if body.ID == "" {
+		abortWithError(c, domain.NewValidationError("id", "id is required"))
 		return
 	}
 
@@ -141,25 +239,26 @@ func (handler *HTTPHandler) Create(c *gin.Context) {
 		user, err := handler.userService.Create(ctx, body.ID, body.Name, body.LastName, body.Email)
 
 		if err != nil {
-			c.AbortWithStatus(http.StatusInternalServerError)
-			handler.logger.Error(ctx, err.Error())
+			abortWithError(c, err)
 			return
 		}
 
+		c.Header("ETag", formatETag(user.Version))
 		c.JSON(http.StatusCreated, dto.CreateUserResponse(user))
 		return
 	}
 
-	c.AbortWithStatus(http.StatusUnauthorized)
+	abortWithError(c, errNotAllowed)
 }
 
 // Update godoc
-// @Summary  update user
+// @Summary  replace user
 // @Schemes
-// @Description  updates a users name, last name
+// @Description  replaces a users name, last name and email, all fields are required
 // @Accept       json
 // @Param        user  body  dto.BodyCreateUser  true  "Update user"
 // @Param        id  path  string  true  "User id"
+// @Param        If-Match  header  string  false  "ETag of the user as last read"
 // @Produce      json
 // @Success      200  {object}  dto.UserResponse
 // @Router       /api/users/{id} [put]
@@ -172,24 +271,237 @@ func (handler *HTTPHandler) Update(c *gin.Context) {
 
 	if auth.AuthorizeAdmin() || auth.AuthorizeMatchingId(c.Param("id")) {
 
+		version, err := parseIfMatch(c.GetHeader("If-Match"))
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
 		body := dto.BodyCreateUser{}
-		err := c.BindJSON(&body)
+		err = c.ShouldBindJSON(&body)
+
+		if err != nil {
+			abortWithError(c, errInvalidBody)
+			return
+		}
+
+		user, err := handler.userService.UpdateUserDetails(ctx, c.Param("id"), version, body.Name, body.LastName, body.Email)
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Header("ETag", formatETag(user.Version))
+		c.JSON(http.StatusOK, dto.CreateUserResponse(user))
+		return
+	}
+
+	abortWithError(c, errNotAllowed)
+}
+
+// Patch godoc
+// @Summary  patch user
+// @Schemes
+// @Description  partially updates a user using a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) document
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Param        patch  body  object  true  "Patch document"
+// @Param        id  path  string  true  "User id"
+// @Param        If-Match  header  string  false  "ETag of the user as last read"
+// @Produce      json
+// @Success      200  {object}  dto.UserResponse
+// @Router       /api/users/{id} [patch]
+func (handler *HTTPHandler) Patch(c *gin.Context) {
+	ctx := c.Request.Context()
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	auth := authorization.NewRest(c)
+
+	if auth.AuthorizeAdmin() || auth.AuthorizeMatchingId(c.Param("id")) {
+
+		version, err := parseIfMatch(c.GetHeader("If-Match"))
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		patch, err := c.GetRawData()
+
+		if err != nil {
+			abortWithError(c, errInvalidBody)
+			return
+		}
+
+		existing, err := handler.userService.Get(ctx, c.Param("id"))
 
 		if err != nil {
-			c.AbortWithStatus(http.StatusBadRequest)
+			abortWithError(c, err)
+			return
+		}
+
+		if err = existing.CheckVersion(version); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		body, err := dto.PatchUser(existing, c.ContentType(), patch)
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		user, err := handler.userService.UpdateUserDetails(ctx, existing.ID, existing.Version, body.Name, body.LastName, body.Email)
+
+		if err != nil {
+			abortWithError(c, err)
+			return
 		}
 
-		user, err := handler.userService.UpdateUserDetails(ctx, c.Param("id"), body.Name, body.LastName, body.Email)
+		c.Header("ETag", formatETag(user.Version))
+		c.JSON(http.StatusOK, dto.CreateUserResponse(user))
+		return
+	}
+
+	abortWithError(c, errNotAllowed)
+}
+
+// Delete godoc
+// @Summary  delete user
+// @Schemes
+// @Description  deletes a user from the system
+// @Param        id  path  string  true  "User id"
+// @Success      204
+// @Router       /api/users/{id} [delete]
+func (handler *HTTPHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	auth := authorization.NewRest(c)
+
+	if auth.AuthorizeAdmin() || auth.AuthorizeMatchingId(c.Param("id")) {
+
+		err := handler.userService.Delete(ctx, c.Param("id"))
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	abortWithError(c, errNotAllowed)
+}
+
+// Erase godoc
+// @Summary  erase user
+// @Schemes
+// @Description  irreversibly anonymizes the personal data of a user (GDPR right to erasure)
+// @Param        id  path  string  true  "User id"
+// @Produce      json
+// @Success      200  {object}  dto.UserResponse
+// @Router       /api/users/{id}/erasure [post]
+func (handler *HTTPHandler) Erase(c *gin.Context) {
+	ctx := c.Request.Context()
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	auth := authorization.NewRest(c)
+
+	if auth.AuthorizeAdmin() || auth.AuthorizeMatchingId(c.Param("id")) {
+
+		user, err := handler.userService.Erase(ctx, c.Param("id"), auth.Id())
 
 		if err != nil {
-			c.AbortWithStatus(http.StatusInternalServerError)
-			handler.logger.Error(ctx, err.Error())
+			abortWithError(c, err)
 			return
 		}
 
 		c.JSON(http.StatusOK, dto.CreateUserResponse(user))
+		return
+	}
+
+	abortWithError(c, errNotAllowed)
+}
+
+// Export godoc
+// @Summary  export user data
+// @Schemes
+// @Description  exports all data stored about a user (GDPR subject access request)
+// @Param        id      path   string  true   "User id"
+// @Param        format  query  string  false  "json (default) or zip"
+// @Produce      json
+// @Produce      application/zip
+// @Success      200  {object}  dto.UserExportResponse
+// @Router       /api/users/{id}/export [get]
+func (handler *HTTPHandler) Export(c *gin.Context) {
+	ctx := c.Request.Context()
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	auth := authorization.NewRest(c)
+
+	if auth.AuthorizeAdmin() || auth.AuthorizeMatchingId(c.Param("id")) {
+
+		export, err := handler.userService.Export(ctx, c.Param("id"))
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		response := dto.CreateUserExportResponse(export)
+
+		if c.Query("format") != "zip" {
+			c.JSON(http.StatusOK, response)
+			return
+		}
+
+		archive, err := zipExport(fmt.Sprintf("user-%s.json", export.User.ID), response)
+
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=user-%s.zip", export.User.ID))
+		c.Data(http.StatusOK, "application/zip", archive)
+		return
+	}
+
+	abortWithError(c, errNotAllowed)
+}
+
+func zipExport(name string, export dto.UserExportResponse) ([]byte, error) {
+	js, err := json.MarshalIndent(export, "", "  ")
+
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := zip.NewWriter(buffer)
+
+	file, err := writer.Create(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = file.Write(js); err != nil {
+		return nil, err
+	}
 
+	if err = writer.Close(); err != nil {
+		return nil, err
 	}
 
-	c.AbortWithStatus(http.StatusUnauthorized)
+	return buffer.Bytes(), nil
 }
@@ -1,29 +1,38 @@
 package handlers
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
+	mock2 "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 	"user-service/config"
 	"user-service/internal/core/domain"
 	"user-service/internal/mock"
 	"user-service/pkg/dto"
+	"user-service/pkg/events"
 	"user-service/pkg/logging"
 )
 
 type RestHandlerTestSuite struct {
 	suite.Suite
-	MockService *mock.UserService
-	TestHandler *HTTPHandler
-	TestRouter  *gin.Engine
-	Cfg         *config.Config
-	TestData    struct {
+	MockService           *mock.UserService
+	MockDeadLetters       *mock.DeadLetterQueue
+	MockOutboxDeadLetters *mock.DeadLetterQueue
+	MockEvents            *mock.PublishedEvents
+	TestHandler           *HTTPHandler
+	TestRouter            *gin.Engine
+	Cfg                   *config.Config
+	TestData              struct {
 		User domain.User
 	}
 }
@@ -46,8 +55,20 @@ func (suite *RestHandlerTestSuite) SetupSuite() {
 	deliveryHandler := NewRest(mockService, router, logger, cfg)
 	deliveryHandler.SetupEndpoints()
 
+	mockDeadLetters := new(mock.DeadLetterQueue)
+	deliveryHandler.SetupDeadLetterEndpoints(mockDeadLetters)
+
+	mockOutboxDeadLetters := new(mock.DeadLetterQueue)
+	deliveryHandler.SetupOutboxDeadLetterEndpoints(mockOutboxDeadLetters)
+
+	mockEvents := new(mock.PublishedEvents)
+	deliveryHandler.SetupDebugEndpoints(mockEvents)
+
 	suite.Cfg = cfg
 	suite.MockService = mockService
+	suite.MockDeadLetters = mockDeadLetters
+	suite.MockOutboxDeadLetters = mockOutboxDeadLetters
+	suite.MockEvents = mockEvents
 	suite.TestRouter = router
 	suite.TestHandler = deliveryHandler
 	suite.TestData = struct {
@@ -64,10 +85,19 @@ func (suite *RestHandlerTestSuite) SetupSuite() {
 
 func (suite *RestHandlerTestSuite) SetupTest() {
 	suite.MockService.ExpectedCalls = nil
+	suite.MockService.Calls = nil
+	suite.MockDeadLetters.ExpectedCalls = nil
+	suite.MockDeadLetters.Calls = nil
+	suite.MockOutboxDeadLetters.ExpectedCalls = nil
+	suite.MockOutboxDeadLetters.Calls = nil
+	suite.MockEvents.ExpectedCalls = nil
+	suite.MockEvents.Calls = nil
 }
 
 func (suite *RestHandlerTestSuite) TestHandler_GetAll() {
-	suite.MockService.On("GetAll").Return([]domain.User{suite.TestData.User}, nil)
+	query, _ := domain.NewUserQuery(0, "", "", "", "")
+
+	suite.MockService.On("GetAll", query).Return(domain.UserPage{Users: []domain.User{suite.TestData.User}, Total: 1}, nil)
 
 	rr := httptest.NewRecorder()
 
@@ -80,20 +110,62 @@ func (suite *RestHandlerTestSuite) TestHandler_GetAll() {
 
 	suite.Equal(http.StatusOK, rr.Code)
 
-	var responseObject dto.UserListResponse
+	var responseObject dto.UserPageResponse
 	err = json.NewDecoder(rr.Body).Decode(&responseObject)
 
 	suite.NoError(err)
 
-	suite.Len(responseObject, 1)
+	suite.Len(responseObject.Users, 1)
+	suite.EqualValues(1, responseObject.Total)
+	suite.Empty(responseObject.NextCursor)
+
+	suite.EqualValues(suite.TestData.User.ID, responseObject.Users[0].ID)
+	suite.EqualValues(suite.TestData.User.Name, responseObject.Users[0].Name)
+	suite.EqualValues(suite.TestData.User.LastName, responseObject.Users[0].LastName)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetAll_Paged() {
+	cursor := domain.EncodeUserCursor(suite.TestData.User, domain.SortByName)
+	query, _ := domain.NewUserQuery(1, cursor, "-name", "test@email.com", "test")
+
+	suite.MockService.On("GetAll", query).Return(domain.UserPage{Users: []domain.User{suite.TestData.User}, NextCursor: cursor, Total: 2}, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/users?limit=1&sort=-name&email=test@email.com&name_prefix=test&cursor="+cursor, nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var responseObject dto.UserPageResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.Len(responseObject.Users, 1)
+	suite.EqualValues(2, responseObject.Total)
+	suite.Equal(cursor, responseObject.NextCursor)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetAll_BadQuery() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/users?sort=password", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
 
-	suite.EqualValues(suite.TestData.User.ID, responseObject[0].ID)
-	suite.EqualValues(suite.TestData.User.Name, responseObject[0].Name)
-	suite.EqualValues(suite.TestData.User.LastName, responseObject[0].LastName)
+	suite.Equal(http.StatusBadRequest, rr.Code)
 }
 
-func (suite *RestHandlerTestSuite) TestHandler_GetAll_NoneFound() {
-	suite.MockService.On("GetAll").Return([]domain.User{}, errors.New("Not found"))
+func (suite *RestHandlerTestSuite) TestHandler_GetAll_Failed() {
+	suite.MockService.On("GetAll", mock2.Anything).Return(domain.UserPage{}, errors.New("could not get users"))
 
 	rr := httptest.NewRecorder()
 
@@ -104,7 +176,60 @@ func (suite *RestHandlerTestSuite) TestHandler_GetAll_NoneFound() {
 
 	suite.TestRouter.ServeHTTP(rr, request)
 
-	suite.Equal(http.StatusNotFound, rr.Code)
+	suite.Equal(http.StatusInternalServerError, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Search() {
+	page, _ := domain.NewSearchPage(2, 10)
+
+	suite.MockService.On("Search", "test", page).Return(domain.UserSearchResult{Users: []domain.User{suite.TestData.User}, Total: 11}, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/users/search?q=Test&page=2&size=10", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var responseObject dto.UserSearchResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.Len(responseObject.Users, 1)
+	suite.EqualValues(11, responseObject.Total)
+	suite.Equal(2, responseObject.Page)
+	suite.EqualValues(suite.TestData.User.ID, responseObject.Users[0].ID)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Search_EmptyQuery() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/users/search?q=", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Search_NoAdmin() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/users/search?q=test", nil)
+	request.Header.Set("X-User-Id", suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusForbidden, rr.Code)
 }
 
 func (suite *RestHandlerTestSuite) TestHandler_Get() {
@@ -144,11 +269,11 @@ func (suite *RestHandlerTestSuite) TestHandler_Get_BadID() {
 
 	suite.TestRouter.ServeHTTP(rr, request)
 
-	suite.Equal(http.StatusUnauthorized, rr.Code)
+	suite.Equal(http.StatusForbidden, rr.Code)
 }
 
 func (suite *RestHandlerTestSuite) TestHandler_Get_NotFound() {
-	suite.MockService.On("Get", suite.TestData.User.ID).Return(domain.User{}, errors.New("Not found"))
+	suite.MockService.On("Get", suite.TestData.User.ID).Return(domain.User{}, domain.NewNotFoundError("user", suite.TestData.User.ID))
 
 	rr := httptest.NewRecorder()
 
@@ -167,7 +292,7 @@ func (suite *RestHandlerTestSuite) TestHandler_Create() {
 
 	rr := httptest.NewRecorder()
 
-	data, err := json.Marshal(dto.BodyCreateUser(suite.TestData.User))
+	data, err := json.Marshal(createUserBody(suite.TestData.User))
 
 	suite.NoError(err)
 
@@ -211,12 +336,58 @@ func (suite *RestHandlerTestSuite) TestHandler_Create_BadInput() {
 	suite.Equal(http.StatusBadRequest, rr.Code)
 }
 
+func (suite *RestHandlerTestSuite) TestHandler_Create_ValidationError() {
+	suite.MockService.On("Create", suite.TestData.User.ID, suite.TestData.User.Name, suite.TestData.User.LastName, suite.TestData.User.Email).Return(domain.User{}, domain.NewValidationError("name", "name not allowed"))
+
+	rr := httptest.NewRecorder()
+
+	data, err := json.Marshal(createUserBody(suite.TestData.User))
+
+	suite.NoError(err)
+
+	request, err := http.NewRequest(http.MethodPost, "/api/users", strings.NewReader(string(data)))
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Create_Conflict() {
+	suite.MockService.On("Create", suite.TestData.User.ID, suite.TestData.User.Name, suite.TestData.User.LastName, suite.TestData.User.Email).Return(domain.User{}, domain.NewConflictError("email", "email is already in use"))
+
+	rr := httptest.NewRecorder()
+
+	data, err := json.Marshal(createUserBody(suite.TestData.User))
+
+	suite.NoError(err)
+
+	request, err := http.NewRequest(http.MethodPost, "/api/users", strings.NewReader(string(data)))
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusConflict, rr.Code)
+
+	var responseObject dto.ProblemResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.Len(responseObject.Errors, 1)
+	suite.Equal("email", responseObject.Errors[0].Field)
+}
+
 func (suite *RestHandlerTestSuite) TestHandler_Create_CouldNotCreate() {
 	suite.MockService.On("Create", suite.TestData.User.ID, suite.TestData.User.Name, suite.TestData.User.LastName, suite.TestData.User.Email).Return(domain.User{}, errors.New("could not create"))
 
 	rr := httptest.NewRecorder()
 
-	data, err := json.Marshal(dto.BodyCreateUser(suite.TestData.User))
+	data, err := json.Marshal(createUserBody(suite.TestData.User))
 
 	suite.NoError(err)
 
@@ -234,11 +405,11 @@ func (suite *RestHandlerTestSuite) TestHandler_Update() {
 	updated := suite.TestData.User
 	updated.Name = "new-name"
 
-	suite.MockService.On("UpdateUserDetails", suite.TestData.User.ID, updated.Name, suite.TestData.User.LastName, suite.TestData.User.Email).Return(updated, nil)
+	suite.MockService.On("UpdateUserDetails", suite.TestData.User.ID, int64(0), updated.Name, suite.TestData.User.LastName, suite.TestData.User.Email).Return(updated, nil)
 
 	rr := httptest.NewRecorder()
 
-	data, err := json.Marshal(dto.BodyCreateUser(updated))
+	data, err := json.Marshal(createUserBody(updated))
 
 	suite.NoError(err)
 
@@ -263,11 +434,11 @@ func (suite *RestHandlerTestSuite) TestHandler_Update_CouldNotCreate() {
 	updated := suite.TestData.User
 	updated.Name = "new-name"
 
-	suite.MockService.On("UpdateUserDetails", suite.TestData.User.ID, updated.Name, suite.TestData.User.LastName, suite.TestData.User.Email).Return(domain.User{}, errors.New("could not update"))
+	suite.MockService.On("UpdateUserDetails", suite.TestData.User.ID, int64(0), updated.Name, suite.TestData.User.LastName, suite.TestData.User.Email).Return(domain.User{}, errors.New("could not update"))
 
 	rr := httptest.NewRecorder()
 
-	data, err := json.Marshal(dto.BodyCreateUser(updated))
+	data, err := json.Marshal(createUserBody(updated))
 
 	suite.NoError(err)
 
@@ -281,7 +452,834 @@ func (suite *RestHandlerTestSuite) TestHandler_Update_CouldNotCreate() {
 	suite.Equal(http.StatusInternalServerError, rr.Code)
 }
 
-func TestIntegration_RestHandlerTestSuite(t *testing.T) {
-	testSuite := new(RestHandlerTestSuite)
-	suite.Run(t, testSuite)
+func (suite *RestHandlerTestSuite) TestHandler_Delete() {
+	suite.MockService.On("Delete", suite.TestData.User.ID).Return(nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), nil)
+	request.Header.Set("X-User-Id", suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusNoContent, rr.Code)
+	suite.MockService.AssertCalled(suite.T(), "Delete", suite.TestData.User.ID)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Delete_Unauthorized() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), nil)
+	request.Header.Set("X-User-Id", "other-id")
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusForbidden, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Delete_CouldNotDelete() {
+	suite.MockService.On("Delete", suite.TestData.User.ID).Return(errors.New("could not delete"))
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusInternalServerError, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Erase() {
+	erased := suite.TestData.User
+	erased.Erase(suite.TestData.User.ID, time.Now())
+
+	suite.MockService.On("Erase", suite.TestData.User.ID, suite.TestData.User.ID).Return(erased, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/users/%s/erasure", suite.TestData.User.ID), nil)
+	request.Header.Set("X-User-Id", suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var responseObject dto.UserResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.EqualValues(erased.ID, responseObject.ID)
+	suite.EqualValues(erased.Email, responseObject.Email)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Erase_Unauthorized() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/users/%s/erasure", suite.TestData.User.ID), nil)
+	request.Header.Set("X-User-Id", "other-id")
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusForbidden, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Export() {
+	user := suite.TestData.User
+	user.Version = 3
+	user.CreatedAt = time.Now().UTC().Add(-time.Hour)
+	user.DeletedAt = gorm.DeletedAt{Time: time.Now().UTC(), Valid: true}
+	export := domain.NewUserExport(user, time.Now().UTC())
+
+	suite.MockService.On("Export", suite.TestData.User.ID).Return(export, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/users/%s/export", suite.TestData.User.ID), nil)
+	request.Header.Set("X-User-Id", suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var responseObject dto.UserExportResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.EqualValues(suite.TestData.User.ID, responseObject.User.ID)
+	suite.EqualValues(suite.TestData.User.Email, responseObject.User.Email)
+	suite.True(export.ExportedAt.Equal(responseObject.ExportedAt))
+	suite.EqualValues(3, responseObject.User.Version)
+	suite.True(user.CreatedAt.Equal(responseObject.User.CreatedAt))
+	suite.Require().NotNil(responseObject.User.DeletedAt)
+	suite.True(user.DeletedAt.Time.Equal(*responseObject.User.DeletedAt))
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Export_Zip() {
+	export := domain.NewUserExport(suite.TestData.User, time.Now().UTC())
+
+	suite.MockService.On("Export", suite.TestData.User.ID).Return(export, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/users/%s/export?format=zip", suite.TestData.User.ID), nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal("application/zip", rr.Header().Get("Content-Type"))
+
+	archive, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+
+	suite.NoError(err)
+	suite.Len(archive.File, 1)
+
+	file, err := archive.File[0].Open()
+
+	suite.NoError(err)
+
+	var responseObject dto.UserExportResponse
+	err = json.NewDecoder(file).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.EqualValues(suite.TestData.User.ID, responseObject.User.ID)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Export_Unauthorized() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/users/%s/export", suite.TestData.User.ID), nil)
+	request.Header.Set("X-User-Id", "other-id")
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusForbidden, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Update_ValidationError() {
+	updated := suite.TestData.User
+	updated.Email = "not-an-email"
+
+	suite.MockService.On("UpdateUserDetails", suite.TestData.User.ID, int64(0), updated.Name, updated.LastName, updated.Email).Return(suite.TestData.User, domain.NewValidationError("email", "email is not valid"))
+
+	rr := httptest.NewRecorder()
+
+	data, err := json.Marshal(createUserBody(updated))
+
+	suite.NoError(err)
+
+	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(string(data)))
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+
+	suite.Equal("application/problem+json", rr.Header().Get("Content-Type"))
+
+	var responseObject dto.ProblemResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.Equal(http.StatusBadRequest, responseObject.Status)
+	suite.Len(responseObject.Errors, 1)
+	suite.Equal("email", responseObject.Errors[0].Field)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Update_BadInput() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader("{"))
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+	suite.MockService.AssertNotCalled(suite.T(), "UpdateUserDetails", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Patch_MergePatch() {
+	updated := suite.TestData.User
+	updated.Name = "new-name"
+
+	suite.MockService.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+	suite.MockService.On("UpdateUserDetails", suite.TestData.User.ID, int64(0), updated.Name, suite.TestData.User.LastName, suite.TestData.User.Email).Return(updated, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(`{"name": "new-name"}`))
+	request.Header.Set("Content-Type", dto.MergePatchContentType)
+	request.Header.Set("X-User-Id", suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var responseObject dto.UserResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.EqualValues(updated.Name, responseObject.Name)
+	suite.EqualValues(suite.TestData.User.LastName, responseObject.LastName)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Patch_JSONPatch() {
+	updated := suite.TestData.User
+	updated.Email = "new@email.com"
+
+	suite.MockService.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+	suite.MockService.On("UpdateUserDetails", suite.TestData.User.ID, int64(0), suite.TestData.User.Name, suite.TestData.User.LastName, updated.Email).Return(updated, nil)
+
+	rr := httptest.NewRecorder()
+
+	patch := `[{"op": "test", "path": "/email", "value": "test@email.com"}, {"op": "replace", "path": "/email", "value": "new@email.com"}]`
+
+	request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(patch))
+	request.Header.Set("Content-Type", dto.JSONPatchContentType)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Patch_FailedTest() {
+	suite.MockService.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+
+	rr := httptest.NewRecorder()
+
+	patch := `[{"op": "test", "path": "/email", "value": "other@email.com"}, {"op": "replace", "path": "/email", "value": "new@email.com"}]`
+
+	request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(patch))
+	request.Header.Set("Content-Type", dto.JSONPatchContentType)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Patch_ChangeID() {
+	suite.MockService.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(`{"id": "other-id"}`))
+	request.Header.Set("Content-Type", dto.MergePatchContentType)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Patch_UnsupportedMediaType() {
+	suite.MockService.On("Get", suite.TestData.User.ID).Return(suite.TestData.User, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(`{"name": "new-name"}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusUnsupportedMediaType, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Patch_NotFound() {
+	suite.MockService.On("Get", suite.TestData.User.ID).Return(domain.User{}, domain.NewNotFoundError("user", suite.TestData.User.ID))
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(`{"name": "new-name"}`))
+	request.Header.Set("Content-Type", dto.MergePatchContentType)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Get_NotFoundProblem() {
+	suite.MockService.On("Get", suite.TestData.User.ID).Return(domain.User{}, domain.NewNotFoundError("user", suite.TestData.User.ID))
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), nil)
+	request.Header.Set("X-User-Id", suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+	suite.Equal("application/problem+json", rr.Header().Get("Content-Type"))
+
+	var responseObject dto.ProblemResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.Equal("/problems/not-found", responseObject.Type)
+	suite.Equal(http.StatusNotFound, responseObject.Status)
+	suite.Equal(fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), responseObject.Instance)
+	suite.NotEmpty(responseObject.Detail)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Update_Conflict() {
+	suite.MockService.On("UpdateUserDetails", suite.TestData.User.ID, int64(0), suite.TestData.User.Name, suite.TestData.User.LastName, suite.TestData.User.Email).Return(suite.TestData.User, domain.NewConflictError("", "user has been erased"))
+
+	rr := httptest.NewRecorder()
+
+	data, err := json.Marshal(createUserBody(suite.TestData.User))
+
+	suite.NoError(err)
+
+	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(string(data)))
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusConflict, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_InternalErrorHidesDetail() {
+	suite.MockService.On("Delete", suite.TestData.User.ID).Return(errors.New("connection refused on 10.0.0.1"))
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusInternalServerError, rr.Code)
+
+	var responseObject dto.ProblemResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.Empty(responseObject.Detail)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Get_ETag() {
+	user := suite.TestData.User
+	user.Version = 4
+
+	suite.MockService.On("Get", suite.TestData.User.ID).Return(user, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), nil)
+	request.Header.Set("X-User-Id", suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal(`"4"`, rr.Header().Get("ETag"))
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Update_IfMatch() {
+	updated := suite.TestData.User
+	updated.Version = 5
+
+	suite.MockService.On("UpdateUserDetails", suite.TestData.User.ID, int64(4), suite.TestData.User.Name, suite.TestData.User.LastName, suite.TestData.User.Email).Return(updated, nil)
+
+	rr := httptest.NewRecorder()
+
+	data, err := json.Marshal(createUserBody(suite.TestData.User))
+
+	suite.NoError(err)
+
+	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(string(data)))
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+	request.Header.Set("If-Match", `"4"`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal(`"5"`, rr.Header().Get("ETag"))
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Update_PreconditionFailed() {
+	suite.MockService.On("UpdateUserDetails", suite.TestData.User.ID, int64(3), suite.TestData.User.Name, suite.TestData.User.LastName, suite.TestData.User.Email).Return(suite.TestData.User, domain.NewPreconditionFailedError("user has been modified"))
+
+	rr := httptest.NewRecorder()
+
+	data, err := json.Marshal(createUserBody(suite.TestData.User))
+
+	suite.NoError(err)
+
+	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(string(data)))
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+	request.Header.Set("If-Match", `"3"`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusPreconditionFailed, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Update_InvalidIfMatch() {
+	rr := httptest.NewRecorder()
+
+	data, err := json.Marshal(createUserBody(suite.TestData.User))
+
+	suite.NoError(err)
+
+	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(string(data)))
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+	request.Header.Set("If-Match", `"abc"`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusPreconditionFailed, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_Patch_PreconditionFailed() {
+	user := suite.TestData.User
+	user.Version = 2
+
+	suite.MockService.On("Get", suite.TestData.User.ID).Return(user, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/api/users/%s", suite.TestData.User.ID), strings.NewReader(`{"name": "new-name"}`))
+	request.Header.Set("Content-Type", dto.MergePatchContentType)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+	request.Header.Set("If-Match", `"1"`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusPreconditionFailed, rr.Code)
+	suite.MockService.AssertNotCalled(suite.T(), "UpdateUserDetails", mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything, mock2.Anything)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetEventSchemas() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/events/schemas", nil)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var responseObject []dto.EventSchemaResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.Len(responseObject, len(events.Definitions))
+	suite.Equal(events.UserCreated, responseObject[0].Type)
+	suite.Equal(1, responseObject[0].Version)
+	suite.True(responseObject[0].Current)
+	suite.Equal("http://"+request.Host+"/api/events/schemas/bikepack.user.created/1", responseObject[0].SchemaURL)
+	suite.Equal(responseObject[0].SchemaURL, responseObject[0].Schema["$id"])
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetEventSchema() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/events/schemas/bikepack.user.erased/1", nil)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Equal("application/schema+json", rr.Header().Get("Content-Type"))
+
+	var schema map[string]interface{}
+	err = json.NewDecoder(rr.Body).Decode(&schema)
+
+	suite.NoError(err)
+
+	suite.Equal("object", schema["type"])
+	suite.Contains(schema["properties"], "erased_at")
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetEventSchema_NotFound() {
+	for _, path := range []string{"/api/events/schemas/bikepack.user.created/99", "/api/events/schemas/unknown/1", "/api/events/schemas/bikepack.user.created/latest"} {
+		rr := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+
+		suite.NoError(err)
+
+		suite.TestRouter.ServeHTTP(rr, request)
+
+		suite.Equal(http.StatusNotFound, rr.Code, path)
+	}
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetDeadLetters() {
+	deadLetter := domain.DeadLetter{
+		ID:          "message-id",
+		Type:        IdentityUserRegistered,
+		Reason:      "invalid user",
+		Attempts:    1,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":"test-id"}`),
+	}
+
+	suite.MockDeadLetters.On("List", 20).Return([]domain.DeadLetter{deadLetter}, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/dead-letters", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var responseObject []dto.DeadLetterResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.Len(responseObject, 1)
+	suite.Equal("message-id", responseObject[0].ID)
+	suite.Equal("invalid user", responseObject[0].Reason)
+	suite.JSONEq(`{"id":"test-id"}`, string(responseObject[0].Body))
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetDeadLetters_BadLimit() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/dead-letters?limit=1000", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusBadRequest, rr.Code)
+	suite.MockDeadLetters.AssertNotCalled(suite.T(), "List", mock2.Anything)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetDeadLetters_NoAdmin() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/dead-letters", nil)
+	request.Header.Set("X-User-Id", suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusForbidden, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetDeadLetter_NotFound() {
+	suite.MockDeadLetters.On("Get", "message-id").Return(domain.DeadLetter{}, domain.NewNotFoundError("dead letter", "message-id"))
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/dead-letters/message-id", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_ReplayDeadLetter() {
+	suite.MockDeadLetters.On("Replay", "message-id").Return(nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, "/api/dead-letters/message-id/replay", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusNoContent, rr.Code)
+	suite.MockDeadLetters.AssertCalled(suite.T(), "Replay", "message-id")
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_ReplayDeadLetter_Poison() {
+	suite.MockDeadLetters.On("Replay", "message-id").Return(newPoisonMessageError("invalid user", domain.NewValidationError("email", "email is not valid")))
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, "/api/dead-letters/message-id/replay", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusUnprocessableEntity, rr.Code)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_DeleteDeadLetter() {
+	suite.MockDeadLetters.On("Delete", "message-id").Return(nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, "/api/dead-letters/message-id", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusNoContent, rr.Code)
+	suite.MockDeadLetters.AssertCalled(suite.T(), "Delete", "message-id")
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_PurgeDeadLetters() {
+	suite.MockDeadLetters.On("Purge").Return(3, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, "/api/dead-letters", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var responseObject dto.DeadLetterPurgeResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+	suite.Equal(3, responseObject.Purged)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetOutboxDeadLetters() {
+	suite.MockOutboxDeadLetters.On("List", 20).Return([]domain.DeadLetter{{
+		ID:          "5",
+		Type:        domain.UserCreatedEvent,
+		Reason:      "broker unavailable",
+		Attempts:    25,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":"test-id"}`),
+	}}, nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/outbox/dead-letters", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var responseObject []dto.DeadLetterResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.Require().Len(responseObject, 1)
+	suite.Equal("5", responseObject[0].ID)
+	suite.MockDeadLetters.AssertNotCalled(suite.T(), "List", mock2.Anything)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_ReplayOutboxDeadLetter() {
+	suite.MockOutboxDeadLetters.On("Replay", "5").Return(nil)
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, "/api/outbox/dead-letters/5/replay", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusNoContent, rr.Code)
+	suite.MockOutboxDeadLetters.AssertCalled(suite.T(), "Replay", "5")
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_ReplayOutboxDeadLetter_NoAdmin() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, "/api/outbox/dead-letters/5/replay", nil)
+	request.Header.Set("X-User-Id", suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusForbidden, rr.Code)
+	suite.MockOutboxDeadLetters.AssertNotCalled(suite.T(), "Replay", mock2.Anything)
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetPublishedEvents() {
+	suite.MockEvents.On("Events").Return([]domain.PublishedEvent{
+		{Topic: "user.create", ID: "first-id", Type: "bikepack.user.created", Subject: "test-id", Data: []byte(`{"id":"test-id"}`)},
+		{Topic: "user.create", ID: "second-id", Type: "bikepack.user.created", Subject: "other-id", Data: []byte(`{"id":"other-id"}`)},
+		{Topic: "user.delete", ID: "third-id", Type: "bikepack.user.deleted", Subject: "test-id", Data: []byte(`{"id":"test-id"}`)},
+	})
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/debug/events?type=bikepack.user.created&subject=test-id", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+
+	var responseObject []dto.PublishedEventResponse
+	err = json.NewDecoder(rr.Body).Decode(&responseObject)
+
+	suite.NoError(err)
+
+	suite.Require().Len(responseObject, 1)
+	suite.Equal("first-id", responseObject[0].ID)
+	suite.JSONEq(`{"id":"test-id"}`, string(responseObject[0].Data))
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetPublishedEvents_Empty() {
+	suite.MockEvents.On("Events").Return([]domain.PublishedEvent{})
+
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/debug/events", nil)
+	request.Header.Set("X-User-Claims", `{"admin": true}`)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.JSONEq(`[]`, rr.Body.String())
+}
+
+func (suite *RestHandlerTestSuite) TestHandler_GetPublishedEvents_Forbidden() {
+	rr := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/debug/events", nil)
+	request.Header.Set("X-User-Id", suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.TestRouter.ServeHTTP(rr, request)
+
+	suite.Equal(http.StatusForbidden, rr.Code)
+	suite.MockEvents.AssertNotCalled(suite.T(), "Events")
+}
+
+func TestIntegration_RestHandlerTestSuite(t *testing.T) {
+	testSuite := new(RestHandlerTestSuite)
+	suite.Run(t, testSuite)
+}
+
+func createUserBody(user domain.User) dto.BodyCreateUser {
+	return dto.BodyCreateUser{
+		ID:       user.ID,
+		Name:     user.Name,
+		LastName: user.LastName,
+		Email:    user.Email,
+	}
 }
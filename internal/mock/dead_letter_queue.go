@@ -0,0 +1,36 @@
+package mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"user-service/internal/core/domain"
+)
+
+type DeadLetterQueue struct {
+	mock.Mock
+}
+
+func (m *DeadLetterQueue) List(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]domain.DeadLetter), args.Error(1)
+}
+
+func (m *DeadLetterQueue) Get(ctx context.Context, id string) (domain.DeadLetter, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.DeadLetter), args.Error(1)
+}
+
+func (m *DeadLetterQueue) Replay(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *DeadLetterQueue) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *DeadLetterQueue) Purge(ctx context.Context) (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
@@ -19,3 +19,13 @@ func (m *MessageBusPublisher) UpdateUserDetails(ctx context.Context, user domain
 	args := m.Called(user)
 	return args.Error(0)
 }
+
+func (m *MessageBusPublisher) DeleteUser(ctx context.Context, user domain.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MessageBusPublisher) EraseUser(ctx context.Context, user domain.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
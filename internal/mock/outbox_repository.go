@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"time"
+	"user-service/internal/core/domain"
+)
+
+type OutboxRepository struct {
+	mock.Mock
+}
+
+func (m *OutboxRepository) Add(ctx context.Context, message domain.OutboxMessage) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
+func (m *OutboxRepository) Claim(ctx context.Context, at, until time.Time, limit int) ([]domain.OutboxMessage, error) {
+	args := m.Called(at, until, limit)
+	return args.Get(0).([]domain.OutboxMessage), args.Error(1)
+}
+
+func (m *OutboxRepository) Delete(ctx context.Context, id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *OutboxRepository) MarkFailed(ctx context.Context, id uint64, reason string, nextAttemptAt time.Time) error {
+	args := m.Called(id, reason, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *OutboxRepository) MarkDead(ctx context.Context, id uint64, reason string, at time.Time) error {
+	args := m.Called(id, reason, at)
+	return args.Error(0)
+}
+
+func (m *OutboxRepository) Scrub(ctx context.Context, user domain.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *OutboxRepository) PurgeDead(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(before)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *OutboxRepository) ListDead(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]domain.OutboxMessage), args.Error(1)
+}
+
+func (m *OutboxRepository) GetDead(ctx context.Context, id uint64) (domain.OutboxMessage, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.OutboxMessage), args.Error(1)
+}
+
+func (m *OutboxRepository) Revive(ctx context.Context, id uint64, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *OutboxRepository) DeleteDead(ctx context.Context, id uint64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,21 @@
+package mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"user-service/internal/core/domain"
+)
+
+type PublishedEvents struct {
+	mock.Mock
+}
+
+func (m *PublishedEvents) Events(ctx context.Context) []domain.PublishedEvent {
+	args := m.Called()
+	return args.Get(0).([]domain.PublishedEvent)
+}
+
+func (m *PublishedEvents) Subscribe(ctx context.Context) <-chan domain.PublishedEvent {
+	args := m.Called()
+	return args.Get(0).(<-chan domain.PublishedEvent)
+}
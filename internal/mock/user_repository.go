@@ -10,9 +10,9 @@ type UserRepository struct {
 	mock.Mock
 }
 
-func (m *UserRepository) GetAll(ctx context.Context) ([]domain.User, error) {
-	args := m.Called()
-	return args.Get(0).([]domain.User), args.Error(1)
+func (m *UserRepository) GetAll(ctx context.Context, query domain.UserQuery) (domain.UserPage, error) {
+	args := m.Called(query)
+	return args.Get(0).(domain.UserPage), args.Error(1)
 }
 
 func (m *UserRepository) Get(ctx context.Context, id string) (domain.User, error) {
@@ -20,6 +20,16 @@ func (m *UserRepository) Get(ctx context.Context, id string) (domain.User, error
 	return args.Get(0).(domain.User), args.Error(1)
 }
 
+func (m *UserRepository) GetUnscoped(ctx context.Context, id string) (domain.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *UserRepository) Search(ctx context.Context, query string, page domain.SearchPage) (domain.UserSearchResult, error) {
+	args := m.Called(query, page)
+	return args.Get(0).(domain.UserSearchResult), args.Error(1)
+}
+
 func (m *UserRepository) Save(ctx context.Context, user domain.User) (domain.User, error) {
 	args := m.Called(user)
 	return args.Get(0).(domain.User), args.Error(1)
@@ -29,3 +39,18 @@ func (m *UserRepository) Update(ctx context.Context, user domain.User) (domain.U
 	args := m.Called(user)
 	return args.Get(0).(domain.User), args.Error(1)
 }
+
+func (m *UserRepository) UpdateUnscoped(ctx context.Context, user domain.User) (domain.User, error) {
+	args := m.Called(user)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *UserRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// Transaction runs fn directly, the mock has no transactional state to roll back.
+func (m *UserRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
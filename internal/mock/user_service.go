@@ -10,9 +10,9 @@ type UserService struct {
 	mock.Mock
 }
 
-func (m *UserService) GetAll(ctx context.Context) ([]domain.User, error) {
-	args := m.Called()
-	return args.Get(0).([]domain.User), args.Error(1)
+func (m *UserService) GetAll(ctx context.Context, query domain.UserQuery) (domain.UserPage, error) {
+	args := m.Called(query)
+	return args.Get(0).(domain.UserPage), args.Error(1)
 }
 
 func (m *UserService) Get(ctx context.Context, id string) (domain.User, error) {
@@ -20,12 +20,32 @@ func (m *UserService) Get(ctx context.Context, id string) (domain.User, error) {
 	return args.Get(0).(domain.User), args.Error(1)
 }
 
+func (m *UserService) Search(ctx context.Context, query string, page domain.SearchPage) (domain.UserSearchResult, error) {
+	args := m.Called(query, page)
+	return args.Get(0).(domain.UserSearchResult), args.Error(1)
+}
+
 func (m *UserService) Create(ctx context.Context, id, name, lastName, email string) (domain.User, error) {
 	args := m.Called(id, name, lastName, email)
 	return args.Get(0).(domain.User), args.Error(1)
 }
 
-func (m *UserService) UpdateUserDetails(ctx context.Context, id, name, lastName, email string) (domain.User, error) {
-	args := m.Called(id, name, lastName, email)
+func (m *UserService) UpdateUserDetails(ctx context.Context, id string, version int64, name, lastName, email string) (domain.User, error) {
+	args := m.Called(id, version, name, lastName, email)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *UserService) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *UserService) Erase(ctx context.Context, id, requestedBy string) (domain.User, error) {
+	args := m.Called(id, requestedBy)
 	return args.Get(0).(domain.User), args.Error(1)
 }
+
+func (m *UserService) Export(ctx context.Context, id string) (domain.UserExport, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.UserExport), args.Error(1)
+}
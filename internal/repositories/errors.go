@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+	"user-service/internal/core/domain"
+
+	"github.com/jackc/pgconn"
+)
+
+const uniqueViolation = "23505"
+
+// sqliteUniqueViolation precedes the constraint name in the message of unique
+// constraint errors of SQLite, which has no separate field for it. The driver
+// wraps the message, e.g. "constraint failed: UNIQUE constraint failed: users.id (1555)".
+const sqliteUniqueViolation = "UNIQUE constraint failed: "
+
+var uniqueConstraintFields = map[string]string{
+	"users_pkey":            "id",
+	"idx_users_email_lower": "email",
+}
+
+// sqliteUniqueConstraints maps the constraint names SQLite reports to the
+// Postgres names; primary keys are reported by column.
+var sqliteUniqueConstraints = map[string]string{
+	"users.id":                      "users_pkey",
+	"index 'idx_users_email_lower'": "idx_users_email_lower",
+}
+
+// translateError converts database specific errors into domain errors where possible.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return conflictError(pgErr.ConstraintName)
+	}
+
+	if _, constraint, ok := strings.Cut(err.Error(), sqliteUniqueViolation); ok {
+		return conflictError(sqliteUniqueConstraints[trimSQLiteCode(constraint)])
+	}
+
+	return err
+}
+
+// trimSQLiteCode removes the result code the driver appends to the message.
+func trimSQLiteCode(constraint string) string {
+	if i := strings.LastIndex(constraint, " ("); i >= 0 {
+		return constraint[:i]
+	}
+
+	return constraint
+}
+
+func conflictError(constraint string) error {
+	field, ok := uniqueConstraintFields[constraint]
+
+	if !ok {
+		return domain.NewConflictError("", "user already exists")
+	}
+
+	return domain.NewConflictError(field, field+" is already in use")
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"user-service/internal/core/domain"
+)
+
+type ErrorsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ErrorsTestSuite) TestErrors_TranslateUniqueEmail() {
+	err := translateError(&pgconn.PgError{Code: uniqueViolation, ConstraintName: "idx_users_email_lower"})
+
+	var conflictErr *domain.ConflictError
+	suite.ErrorAs(err, &conflictErr)
+	suite.Equal("email", conflictErr.Field)
+}
+
+func (suite *ErrorsTestSuite) TestErrors_TranslateUniqueID() {
+	err := translateError(&pgconn.PgError{Code: uniqueViolation, ConstraintName: "users_pkey"})
+
+	var conflictErr *domain.ConflictError
+	suite.ErrorAs(err, &conflictErr)
+	suite.Equal("id", conflictErr.Field)
+}
+
+func (suite *ErrorsTestSuite) TestErrors_TranslateSQLiteUniqueEmail() {
+	err := translateError(errors.New("constraint failed: UNIQUE constraint failed: index 'idx_users_email_lower' (2067)"))
+
+	var conflictErr *domain.ConflictError
+	suite.ErrorAs(err, &conflictErr)
+	suite.Equal("email", conflictErr.Field)
+}
+
+func (suite *ErrorsTestSuite) TestErrors_TranslateSQLiteUniqueID() {
+	err := translateError(errors.New("constraint failed: UNIQUE constraint failed: users.id (1555)"))
+
+	var conflictErr *domain.ConflictError
+	suite.ErrorAs(err, &conflictErr)
+	suite.Equal("id", conflictErr.Field)
+}
+
+func (suite *ErrorsTestSuite) TestErrors_TranslateOther() {
+	original := errors.New("connection refused")
+
+	suite.Equal(original, translateError(original))
+}
+
+func TestUnit_ErrorsTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorsTestSuite))
+}
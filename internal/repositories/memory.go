@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"user-service/internal/core/domain"
+)
+
+type memoryTransactionKey struct{}
+
+// MemoryStore holds the data of the in-memory repositories. Repositories
+// created from the same store share its transactions, like repositories sharing
+// a database connection. It is safe for concurrent use.
+type MemoryStore struct {
+	mutex        sync.Mutex
+	users        map[string]domain.User
+	outbox       []domain.OutboxMessage
+	nextOutboxID uint64
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{users: make(map[string]domain.User), nextOutboxID: 1}
+}
+
+// transaction runs fn holding the lock of the store, so transactions are
+// serialized. The data is restored when fn fails. Nested calls join the outer
+// transaction; fn must not use the store from other goroutines.
+func (store *MemoryStore) transaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if store.inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	users := make(map[string]domain.User, len(store.users))
+
+	for id, user := range store.users {
+		users[id] = user
+	}
+
+	outbox := append([]domain.OutboxMessage(nil), store.outbox...)
+	nextOutboxID := store.nextOutboxID
+	committed := false
+
+	defer func() {
+		if !committed {
+			store.users, store.outbox, store.nextOutboxID = users, outbox, nextOutboxID
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, memoryTransactionKey{}, store)); err != nil {
+		return err
+	}
+
+	committed = true
+
+	return nil
+}
+
+// do runs fn holding the lock of the store, unless ctx carries a transaction
+// of the store that already holds it.
+func (store *MemoryStore) do(ctx context.Context, fn func() error) error {
+	if store.inTransaction(ctx) {
+		return fn()
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return fn()
+}
+
+func (store *MemoryStore) inTransaction(ctx context.Context) bool {
+	tx, ok := ctx.Value(memoryTransactionKey{}).(*MemoryStore)
+	return ok && tx == store
+}
@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+	"user-service/internal/core/domain"
+)
+
+// memoryOutboxRepository keeps the outbox in a MemoryStore.
+type memoryOutboxRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryOutboxRepository(store *MemoryStore) *memoryOutboxRepository {
+	return &memoryOutboxRepository{store: store}
+}
+
+func (repository *memoryOutboxRepository) Add(ctx context.Context, message domain.OutboxMessage) error {
+	return repository.store.do(ctx, func() error {
+		message.ID = repository.store.nextOutboxID
+		repository.store.nextOutboxID++
+
+		if message.CreatedAt.IsZero() {
+			message.CreatedAt = time.Now().UTC()
+		}
+
+		repository.store.outbox = append(repository.store.outbox, message)
+
+		return nil
+	})
+}
+
+func (repository *memoryOutboxRepository) Claim(ctx context.Context, at, until time.Time, limit int) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+
+	err := repository.store.do(ctx, func() error {
+		blocked := make(map[string]bool)
+
+		for i, message := range repository.store.outbox {
+			if len(messages) == limit {
+				break
+			}
+
+			if message.DeadAt != nil || blocked[message.AggregateID] {
+				continue
+			}
+
+			blocked[message.AggregateID] = true
+
+			if message.IsDue(at) && !message.IsClaimed(at) {
+				repository.store.outbox[i].ClaimedUntil = &until
+				messages = append(messages, repository.store.outbox[i])
+			}
+		}
+
+		return nil
+	})
+
+	return messages, err
+}
+
+func (repository *memoryOutboxRepository) Delete(ctx context.Context, id uint64) error {
+	return repository.store.do(ctx, func() error {
+		for i, message := range repository.store.outbox {
+			if message.ID == id {
+				repository.store.outbox = append(repository.store.outbox[:i:i], repository.store.outbox[i+1:]...)
+				break
+			}
+		}
+
+		return nil
+	})
+}
+
+func (repository *memoryOutboxRepository) MarkFailed(ctx context.Context, id uint64, reason string, nextAttemptAt time.Time) error {
+	return repository.store.do(ctx, func() error {
+		for i := range repository.store.outbox {
+			if repository.store.outbox[i].ID == id {
+				repository.store.outbox[i].Attempts++
+				repository.store.outbox[i].LastError = reason
+				repository.store.outbox[i].NextAttemptAt = nextAttemptAt
+				repository.store.outbox[i].ClaimedUntil = nil
+			}
+		}
+
+		return nil
+	})
+}
+
+func (repository *memoryOutboxRepository) MarkDead(ctx context.Context, id uint64, reason string, at time.Time) error {
+	return repository.store.do(ctx, func() error {
+		for i := range repository.store.outbox {
+			if repository.store.outbox[i].ID == id {
+				repository.store.outbox[i].Attempts++
+				repository.store.outbox[i].LastError = reason
+				repository.store.outbox[i].DeadAt = &at
+				repository.store.outbox[i].ClaimedUntil = nil
+			}
+		}
+
+		return nil
+	})
+}
+
+func (repository *memoryOutboxRepository) Scrub(ctx context.Context, user domain.User) error {
+	payload, err := json.Marshal(user)
+
+	if err != nil {
+		return err
+	}
+
+	return repository.store.do(ctx, func() error {
+		for i, message := range repository.store.outbox {
+			if message.AggregateID == user.ID {
+				repository.store.outbox[i].Payload = payload
+			}
+		}
+
+		return nil
+	})
+}
+
+func (repository *memoryOutboxRepository) PurgeDead(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+
+	err := repository.store.do(ctx, func() error {
+		kept := repository.store.outbox[:0:0]
+
+		for _, message := range repository.store.outbox {
+			if message.DeadAt != nil && message.DeadAt.Before(before) {
+				purged++
+				continue
+			}
+
+			kept = append(kept, message)
+		}
+
+		repository.store.outbox = kept
+
+		return nil
+	})
+
+	return purged, err
+}
+
+func (repository *memoryOutboxRepository) ListDead(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+
+	err := repository.store.do(ctx, func() error {
+		for _, message := range repository.store.outbox {
+			if len(messages) == limit {
+				break
+			}
+
+			if message.DeadAt != nil {
+				messages = append(messages, message)
+			}
+		}
+
+		return nil
+	})
+
+	return messages, err
+}
+
+func (repository *memoryOutboxRepository) GetDead(ctx context.Context, id uint64) (domain.OutboxMessage, error) {
+	var found domain.OutboxMessage
+
+	err := repository.store.do(ctx, func() error {
+		i := repository.findDead(id)
+
+		if i < 0 {
+			return domain.NewNotFoundError("outbox message", strconv.FormatUint(id, 10))
+		}
+
+		found = repository.store.outbox[i]
+
+		return nil
+	})
+
+	return found, err
+}
+
+func (repository *memoryOutboxRepository) Revive(ctx context.Context, id uint64, at time.Time) error {
+	return repository.store.do(ctx, func() error {
+		i := repository.findDead(id)
+
+		if i < 0 {
+			return domain.NewNotFoundError("outbox message", strconv.FormatUint(id, 10))
+		}
+
+		message := &repository.store.outbox[i]
+		message.Attempts = 0
+		message.LastError = ""
+		message.NextAttemptAt = at
+		message.ClaimedUntil = nil
+		message.DeadAt = nil
+
+		return nil
+	})
+}
+
+func (repository *memoryOutboxRepository) DeleteDead(ctx context.Context, id uint64) error {
+	return repository.store.do(ctx, func() error {
+		i := repository.findDead(id)
+
+		if i < 0 {
+			return domain.NewNotFoundError("outbox message", strconv.FormatUint(id, 10))
+		}
+
+		repository.store.outbox = append(repository.store.outbox[:i:i], repository.store.outbox[i+1:]...)
+
+		return nil
+	})
+}
+
+// findDead returns the index of the dead message with the given id, or -1.
+// The caller must hold the store.
+func (repository *memoryOutboxRepository) findDead(id uint64) int {
+	for i, message := range repository.store.outbox {
+		if message.ID == id && message.DeadAt != nil {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,346 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"user-service/internal/core/domain"
+
+	"gorm.io/gorm"
+)
+
+// memoryUserRepository keeps the users in a MemoryStore with the semantics of
+// userRepository: deletes are soft, ids of deleted users stay taken, emails are
+// unique among the remaining users regardless of case and updates only change
+// the non-zero fields of the user. Sorting compares strings byte by byte
+// instead of using a database collation. Search only matches substrings.
+type memoryUserRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryUserRepository(store *MemoryStore) *memoryUserRepository {
+	return &memoryUserRepository{store: store}
+}
+
+func (repository *memoryUserRepository) Get(ctx context.Context, id string) (domain.User, error) {
+	var user domain.User
+
+	err := repository.store.do(ctx, func() error {
+		var ok bool
+
+		if user, ok = repository.find(id); !ok {
+			return domain.NewNotFoundError("user", id)
+		}
+
+		return nil
+	})
+
+	return user, err
+}
+
+func (repository *memoryUserRepository) GetUnscoped(ctx context.Context, id string) (domain.User, error) {
+	var user domain.User
+
+	err := repository.store.do(ctx, func() error {
+		var ok bool
+
+		if user, ok = repository.store.users[id]; !ok {
+			return domain.NewNotFoundError("user", id)
+		}
+
+		return nil
+	})
+
+	return user, err
+}
+
+func (repository *memoryUserRepository) GetAll(ctx context.Context, query domain.UserQuery) (domain.UserPage, error) {
+	var result domain.UserPage
+
+	err := repository.store.do(ctx, func() error {
+		users := repository.filter(func(user domain.User) bool {
+			return (query.Email == "" || user.Email == query.Email) &&
+				(query.NamePrefix == "" || strings.HasPrefix(user.Name, query.NamePrefix))
+		})
+
+		result.Total = int64(len(users))
+
+		sort.Slice(users, func(i, j int) bool {
+			return (compareUsers(users[i], users[j], query.Sort) < 0) != query.Descending
+		})
+
+		if query.Cursor != nil {
+			cursorValue, err := query.CursorValue()
+
+			if err != nil {
+				return err
+			}
+
+			cursor := domain.User{ID: query.Cursor.ID}
+			setSortValue(&cursor, query.Sort, cursorValue)
+
+			start := sort.Search(len(users), func(i int) bool {
+				comparison := compareUsers(users[i], cursor, query.Sort)
+				return (comparison > 0 && !query.Descending) || (comparison < 0 && query.Descending)
+			})
+
+			users = users[start:]
+		}
+
+		if len(users) > query.Limit {
+			result.Users = users[:query.Limit]
+			result.NextCursor = domain.EncodeUserCursor(result.Users[query.Limit-1], query.Sort)
+		} else {
+			result.Users = users
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Search matches the query as a case-insensitive substring of the name, last
+// name or email. Exact matches rank before prefix matches, which rank before
+// other matches; ties are ordered by id.
+func (repository *memoryUserRepository) Search(ctx context.Context, query string, page domain.SearchPage) (domain.UserSearchResult, error) {
+	var result domain.UserSearchResult
+
+	query = strings.ToLower(query)
+
+	err := repository.store.do(ctx, func() error {
+		users := repository.filter(func(user domain.User) bool {
+			return searchRank(user, query) > 0
+		})
+
+		sort.Slice(users, func(i, j int) bool {
+			rankI, rankJ := searchRank(users[i], query), searchRank(users[j], query)
+
+			if rankI != rankJ {
+				return rankI > rankJ
+			}
+
+			return users[i].ID < users[j].ID
+		})
+
+		result.Total = int64(len(users))
+
+		if offset := page.Offset(); offset < len(users) {
+			users = users[offset:]
+		} else {
+			users = nil
+		}
+
+		if len(users) > page.Size {
+			users = users[:page.Size]
+		}
+
+		result.Users = users
+
+		return nil
+	})
+
+	return result, err
+}
+
+func (repository *memoryUserRepository) Save(ctx context.Context, user domain.User) (domain.User, error) {
+	err := repository.store.do(ctx, func() error {
+		if _, ok := repository.store.users[user.ID]; ok {
+			return domain.NewConflictError("id", "id is already in use")
+		}
+
+		if repository.emailInUse(user.ID, user.Email) {
+			return domain.NewConflictError("email", "email is already in use")
+		}
+
+		if user.Version == 0 {
+			user.Version = 1
+		}
+
+		if user.CreatedAt.IsZero() {
+			user.CreatedAt = time.Now().UTC()
+		}
+
+		repository.store.users[user.ID] = user
+
+		return nil
+	})
+
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+func (repository *memoryUserRepository) Update(ctx context.Context, user domain.User) (domain.User, error) {
+	return repository.update(ctx, user, repository.find)
+}
+
+func (repository *memoryUserRepository) UpdateUnscoped(ctx context.Context, user domain.User) (domain.User, error) {
+	return repository.update(ctx, user, func(id string) (domain.User, bool) {
+		stored, ok := repository.store.users[id]
+		return stored, ok
+	})
+}
+
+func (repository *memoryUserRepository) update(ctx context.Context, user domain.User, find func(id string) (domain.User, bool)) (domain.User, error) {
+	expected := user.Version
+	user.Version++
+
+	err := repository.store.do(ctx, func() error {
+		stored, ok := find(user.ID)
+
+		if !ok || stored.Version != expected {
+			return domain.NewPreconditionFailedError("user has been modified")
+		}
+
+		if user.Email != "" && repository.emailInUse(user.ID, user.Email) {
+			return domain.NewConflictError("email", "email is already in use")
+		}
+
+		repository.store.users[user.ID] = mergeUser(stored, user)
+
+		return nil
+	})
+
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+func (repository *memoryUserRepository) Delete(ctx context.Context, id string) error {
+	return repository.store.do(ctx, func() error {
+		user, ok := repository.find(id)
+
+		if !ok {
+			return domain.NewNotFoundError("user", id)
+		}
+
+		user.DeletedAt = gorm.DeletedAt{Time: time.Now().UTC(), Valid: true}
+		repository.store.users[id] = user
+
+		return nil
+	})
+}
+
+func (repository *memoryUserRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return repository.store.transaction(ctx, fn)
+}
+
+// find returns the user unless it does not exist or has been deleted.
+func (repository *memoryUserRepository) find(id string) (domain.User, bool) {
+	user, ok := repository.store.users[id]
+	return user, ok && !user.DeletedAt.Valid
+}
+
+func (repository *memoryUserRepository) filter(keep func(user domain.User) bool) []domain.User {
+	var users []domain.User
+
+	for _, user := range repository.store.users {
+		if !user.DeletedAt.Valid && keep(user) {
+			users = append(users, user)
+		}
+	}
+
+	return users
+}
+
+func (repository *memoryUserRepository) emailInUse(id, email string) bool {
+	for _, user := range repository.store.users {
+		if user.ID != id && !user.DeletedAt.Valid && strings.EqualFold(user.Email, email) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mergeUser applies the non-zero fields of update to stored, like an update
+// with a struct in GORM.
+func mergeUser(stored, update domain.User) domain.User {
+	if update.Name != "" {
+		stored.Name = update.Name
+	}
+
+	if update.LastName != "" {
+		stored.LastName = update.LastName
+	}
+
+	if update.Email != "" {
+		stored.Email = update.Email
+	}
+
+	if update.ErasedAt != nil {
+		stored.ErasedAt = update.ErasedAt
+	}
+
+	if update.ErasedBy != "" {
+		stored.ErasedBy = update.ErasedBy
+	}
+
+	if !update.CreatedAt.IsZero() {
+		stored.CreatedAt = update.CreatedAt
+	}
+
+	stored.Version = update.Version
+
+	return stored
+}
+
+// compareUsers orders users by the sort field and then by id.
+func compareUsers(a, b domain.User, field string) int {
+	var comparison int
+
+	if field == domain.SortByCreatedAt {
+		switch {
+		case a.CreatedAt.Before(b.CreatedAt):
+			comparison = -1
+		case a.CreatedAt.After(b.CreatedAt):
+			comparison = 1
+		}
+	} else {
+		comparison = strings.Compare(a.SortValue(field), b.SortValue(field))
+	}
+
+	if comparison != 0 {
+		return comparison
+	}
+
+	return strings.Compare(a.ID, b.ID)
+}
+
+func setSortValue(user *domain.User, field string, value interface{}) {
+	switch field {
+	case domain.SortByName:
+		user.Name, _ = value.(string)
+	case domain.SortByLastName:
+		user.LastName, _ = value.(string)
+	case domain.SortByEmail:
+		user.Email, _ = value.(string)
+	default:
+		user.CreatedAt, _ = value.(time.Time)
+	}
+}
+
+func searchRank(user domain.User, query string) int {
+	rank := 0
+
+	for _, field := range []string{user.Name, user.LastName, user.Email} {
+		field = strings.ToLower(field)
+
+		switch {
+		case field == query:
+			return 3
+		case strings.HasPrefix(field, query) && rank < 2:
+			rank = 2
+		case strings.Contains(field, query) && rank < 1:
+			rank = 1
+		}
+	}
+
+	return rank
+}
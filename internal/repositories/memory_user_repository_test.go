@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"testing"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+)
+
+type MemoryUserRepositoryTestSuite struct {
+	suite.Suite
+	TestRepo *memoryUserRepository
+	TestData struct {
+		User domain.User
+	}
+}
+
+func (suite *MemoryUserRepositoryTestSuite) SetupTest() {
+	suite.TestRepo = NewMemoryUserRepository(NewMemoryStore())
+	suite.TestData = struct {
+		User domain.User
+	}{
+		User: domain.User{
+			ID:       "test-id",
+			Name:     "test-name",
+			LastName: "test-lastname",
+			Email:    "test@email.com",
+			Version:  1,
+		},
+	}
+
+	_, err := suite.TestRepo.Save(context.Background(), suite.TestData.User)
+	suite.Require().NoError(err)
+}
+
+func (suite *MemoryUserRepositoryTestSuite) save(id, name, email string) {
+	_, err := suite.TestRepo.Save(context.Background(), domain.User{ID: id, Name: name, LastName: "test-lastname", Email: email})
+	suite.Require().NoError(err)
+}
+
+func (suite *MemoryUserRepositoryTestSuite) TestRepository_Search() {
+	suite.save("search-id-1", "johanna", "johanna@email.com")
+	suite.save("search-id-2", "john", "john@email.com")
+	suite.save("search-id-3", "mary-johan", "mary@email.com")
+
+	page, _ := domain.NewSearchPage(1, 10)
+
+	result, err := suite.TestRepo.Search(context.Background(), "johan", page)
+
+	suite.NoError(err)
+
+	suite.EqualValues(2, result.Total)
+	suite.Require().Len(result.Users, 2)
+	suite.Equal("search-id-1", result.Users[0].ID)
+	suite.Equal("search-id-3", result.Users[1].ID)
+}
+
+func (suite *MemoryUserRepositoryTestSuite) TestRepository_Concurrent() {
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_ = suite.TestRepo.Transaction(context.Background(), func(ctx context.Context) error {
+				user, err := suite.TestRepo.Get(ctx, suite.TestData.User.ID)
+
+				if err != nil {
+					return err
+				}
+
+				_, err = suite.TestRepo.Update(ctx, user)
+
+				return err
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	user, err := suite.TestRepo.Get(context.Background(), suite.TestData.User.ID)
+
+	suite.NoError(err)
+	suite.EqualValues(21, user.Version)
+}
+
+func TestUnit_MemoryUserRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(MemoryUserRepositoryTestSuite))
+}
+
+func TestUnit_MemoryRepositoryContractTestSuite(t *testing.T) {
+	suite.Run(t, newRepositoryContractTestSuite(func(t *testing.T) (interfaces.UserRepository, interfaces.OutboxRepository) {
+		store := NewMemoryStore()
+		return NewMemoryUserRepository(store), NewMemoryOutboxRepository(store)
+	}))
+}
@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strconv"
+	"time"
+	"user-service/internal/core/domain"
+)
+
+type outboxRepository struct {
+	Connection *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) (*outboxRepository, error) {
+	err := db.AutoMigrate(&domain.OutboxMessage{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &outboxRepository{Connection: db}, nil
+}
+
+func (repository *outboxRepository) Add(ctx context.Context, message domain.OutboxMessage) error {
+	return connection(ctx, repository.Connection).Create(&message).Error
+}
+
+// Claim selects and claims the messages in one short transaction. The rows are
+// selected with FOR UPDATE SKIP LOCKED, so concurrent claims don't wait for each
+// other. SQLite has no row locks, but only allows a single writer anyway.
+func (repository *outboxRepository) Claim(ctx context.Context, at, until time.Time, limit int) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+
+	err := transaction(ctx, repository.Connection, func(ctx context.Context) error {
+		db := connection(ctx, repository.Connection).
+			Where("next_attempt_at <= ? AND dead_at IS NULL", at.UTC()).
+			Where("(claimed_until IS NULL OR claimed_until <= ?)", at.UTC()).
+			Where("NOT EXISTS (SELECT 1 FROM outbox_messages AS older WHERE older.aggregate_id = outbox_messages.aggregate_id " +
+				"AND older.id < outbox_messages.id AND older.dead_at IS NULL)")
+
+		if !isSQLite(repository.Connection) {
+			db = db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		if err := db.Order("id").Limit(limit).Find(&messages).Error; err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, len(messages))
+
+		for i := range messages {
+			ids[i] = messages[i].ID
+			messages[i].ClaimedUntil = &until
+		}
+
+		return connection(ctx, repository.Connection).Model(&domain.OutboxMessage{}).
+			Where("id IN ?", ids).Update("claimed_until", until.UTC()).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (repository *outboxRepository) Delete(ctx context.Context, id uint64) error {
+	return connection(ctx, repository.Connection).Delete(&domain.OutboxMessage{}, id).Error
+}
+
+func (repository *outboxRepository) MarkFailed(ctx context.Context, id uint64, reason string, nextAttemptAt time.Time) error {
+	return connection(ctx, repository.Connection).Model(&domain.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      reason,
+		"next_attempt_at": nextAttemptAt,
+		"claimed_until":   nil,
+	}).Error
+}
+
+func (repository *outboxRepository) MarkDead(ctx context.Context, id uint64, reason string, at time.Time) error {
+	return connection(ctx, repository.Connection).Model(&domain.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":      gorm.Expr("attempts + 1"),
+		"last_error":    reason,
+		"dead_at":       at,
+		"claimed_until": nil,
+	}).Error
+}
+
+func (repository *outboxRepository) Scrub(ctx context.Context, user domain.User) error {
+	payload, err := json.Marshal(user)
+
+	if err != nil {
+		return err
+	}
+
+	return connection(ctx, repository.Connection).Model(&domain.OutboxMessage{}).
+		Where("aggregate_id = ?", user.ID).Update("payload", payload).Error
+}
+
+func (repository *outboxRepository) PurgeDead(ctx context.Context, before time.Time) (int, error) {
+	result := connection(ctx, repository.Connection).Where("dead_at < ?", before.UTC()).Delete(&domain.OutboxMessage{})
+
+	return int(result.RowsAffected), result.Error
+}
+
+func (repository *outboxRepository) ListDead(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+
+	err := connection(ctx, repository.Connection).Where("dead_at IS NOT NULL").Order("id").Limit(limit).Find(&messages).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (repository *outboxRepository) GetDead(ctx context.Context, id uint64) (domain.OutboxMessage, error) {
+	var message domain.OutboxMessage
+
+	result := connection(ctx, repository.Connection).Where("dead_at IS NOT NULL").First(&message, "id = ?", id)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.OutboxMessage{}, domain.NewNotFoundError("outbox message", strconv.FormatUint(id, 10))
+	}
+
+	if result.Error != nil {
+		return domain.OutboxMessage{}, result.Error
+	}
+
+	return message, nil
+}
+
+func (repository *outboxRepository) Revive(ctx context.Context, id uint64, at time.Time) error {
+	result := connection(ctx, repository.Connection).Model(&domain.OutboxMessage{}).
+		Where("id = ? AND dead_at IS NOT NULL", id).Updates(map[string]interface{}{
+		"attempts":        0,
+		"last_error":      "",
+		"next_attempt_at": at.UTC(),
+		"claimed_until":   nil,
+		"dead_at":         nil,
+	})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.NewNotFoundError("outbox message", strconv.FormatUint(id, 10))
+	}
+
+	return nil
+}
+
+func (repository *outboxRepository) DeleteDead(ctx context.Context, id uint64) error {
+	result := connection(ctx, repository.Connection).Where("dead_at IS NOT NULL").Delete(&domain.OutboxMessage{}, "id = ?", id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.NewNotFoundError("outbox message", strconv.FormatUint(id, 10))
+	}
+
+	return nil
+}
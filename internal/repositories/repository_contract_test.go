@@ -0,0 +1,593 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+)
+
+// newRepositories returns empty repositories of one storage driver. The user
+// and outbox repository share their transactions.
+type newRepositories func(t *testing.T) (interfaces.UserRepository, interfaces.OutboxRepository)
+
+// RepositoryContractTestSuite holds the behaviour every storage driver must
+// have. It runs against the in-memory, SQLite and PostgreSQL repositories, so
+// their behaviour can't drift apart.
+type RepositoryContractTestSuite struct {
+	suite.Suite
+	NewRepositories newRepositories
+	TestRepo        interfaces.UserRepository
+	TestOutboxRepo  interfaces.OutboxRepository
+	TestData        struct {
+		User domain.User
+	}
+}
+
+func newRepositoryContractTestSuite(newRepositories newRepositories) *RepositoryContractTestSuite {
+	return &RepositoryContractTestSuite{NewRepositories: newRepositories}
+}
+
+func (suite *RepositoryContractTestSuite) SetupTest() {
+	suite.TestRepo, suite.TestOutboxRepo = suite.NewRepositories(suite.T())
+	suite.TestData = struct {
+		User domain.User
+	}{
+		User: domain.User{
+			ID:       "test-id",
+			Name:     "test-name",
+			LastName: "test-lastname",
+			Email:    "test@email.com",
+			Version:  1,
+		},
+	}
+
+	_, err := suite.TestRepo.Save(context.Background(), suite.TestData.User)
+	suite.Require().NoError(err)
+}
+
+func (suite *RepositoryContractTestSuite) save(id, name, email string) {
+	_, err := suite.TestRepo.Save(context.Background(), domain.User{ID: id, Name: name, LastName: "test-lastname", Email: email})
+	suite.Require().NoError(err)
+}
+
+func (suite *RepositoryContractTestSuite) addMessage(ctx context.Context, aggregateID string, at time.Time) {
+	message, err := domain.NewOutboxMessage(domain.UserCreatedEvent, domain.User{ID: aggregateID}, at)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.TestOutboxRepo.Add(ctx, message))
+}
+
+// pending claims the messages due at the given time only until then, so they
+// can be claimed again.
+func (suite *RepositoryContractTestSuite) pending(ctx context.Context, at time.Time, limit int) []domain.OutboxMessage {
+	messages, err := suite.TestOutboxRepo.Claim(ctx, at, at, limit)
+	suite.Require().NoError(err)
+
+	return messages
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Get() {
+	result, err := suite.TestRepo.Get(context.Background(), suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	suite.False(result.CreatedAt.IsZero())
+	result.CreatedAt = time.Time{}
+
+	suite.EqualValues(suite.TestData.User, result)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Get_NotFound() {
+	_, err := suite.TestRepo.Get(context.Background(), "test")
+
+	suite.ErrorIs(err, domain.ErrNotFound)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_GetAll_Paged() {
+	suite.save("paged-id-3", "paged-c", "paged-3@email.com")
+	suite.save("paged-id-1", "paged-a", "paged-1@email.com")
+	suite.save("paged-id-2", "paged-b", "paged-2@email.com")
+
+	query, _ := domain.NewUserQuery(2, "", domain.SortByName, "", "paged")
+
+	first, err := suite.TestRepo.GetAll(context.Background(), query)
+
+	suite.NoError(err)
+
+	suite.EqualValues(3, first.Total)
+	suite.Require().Len(first.Users, 2)
+	suite.Equal("paged-id-1", first.Users[0].ID)
+	suite.Equal("paged-id-2", first.Users[1].ID)
+	suite.NotEmpty(first.NextCursor)
+
+	query, _ = domain.NewUserQuery(2, first.NextCursor, domain.SortByName, "", "paged")
+
+	second, err := suite.TestRepo.GetAll(context.Background(), query)
+
+	suite.NoError(err)
+
+	suite.Require().Len(second.Users, 1)
+	suite.Equal("paged-id-3", second.Users[0].ID)
+	suite.Empty(second.NextCursor)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_GetAll_Descending() {
+	suite.save("desc-id-1", "desc-a", "desc-1@email.com")
+	suite.save("desc-id-2", "desc-b", "desc-2@email.com")
+	suite.save("desc-id-3", "desc-c", "desc-3@email.com")
+
+	query, _ := domain.NewUserQuery(2, "", "-"+domain.SortByName, "", "desc")
+
+	first, err := suite.TestRepo.GetAll(context.Background(), query)
+
+	suite.NoError(err)
+	suite.Require().Len(first.Users, 2)
+	suite.Equal("desc-id-3", first.Users[0].ID)
+	suite.Equal("desc-id-2", first.Users[1].ID)
+
+	query, _ = domain.NewUserQuery(2, first.NextCursor, "-"+domain.SortByName, "", "desc")
+
+	second, err := suite.TestRepo.GetAll(context.Background(), query)
+
+	suite.NoError(err)
+	suite.Require().Len(second.Users, 1)
+	suite.Equal("desc-id-1", second.Users[0].ID)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_GetAll_CreatedAtCursor() {
+	suite.save("created-id-1", "created", "created-1@email.com")
+	suite.save("created-id-2", "created", "created-2@email.com")
+
+	query, _ := domain.NewUserQuery(1, "", "", "", "created")
+
+	first, err := suite.TestRepo.GetAll(context.Background(), query)
+
+	suite.NoError(err)
+	suite.Require().Len(first.Users, 1)
+
+	query, _ = domain.NewUserQuery(1, first.NextCursor, "", "", "created")
+
+	second, err := suite.TestRepo.GetAll(context.Background(), query)
+
+	suite.NoError(err)
+	suite.Require().Len(second.Users, 1)
+	suite.NotEqual(first.Users[0].ID, second.Users[0].ID)
+	suite.Empty(second.NextCursor)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_GetAll_FilterEmail() {
+	suite.save("other-id", "other", "other@email.com")
+
+	query, _ := domain.NewUserQuery(0, "", "", "TEST@email.com", "")
+
+	result, err := suite.TestRepo.GetAll(context.Background(), query)
+
+	suite.NoError(err)
+	suite.EqualValues(1, result.Total)
+	suite.Require().Len(result.Users, 1)
+	suite.Equal(suite.TestData.User.ID, result.Users[0].ID)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Search_NoResults() {
+	page, _ := domain.NewSearchPage(1, 10)
+
+	result, err := suite.TestRepo.Search(context.Background(), "zzzzzzzzzz", page)
+
+	suite.NoError(err)
+
+	suite.Empty(result.Users)
+	suite.EqualValues(0, result.Total)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Save_DuplicateEmail() {
+	newUser := suite.TestData.User
+	newUser.ID = "test-id-5"
+	newUser.Email = "TEST@email.com"
+
+	_, err := suite.TestRepo.Save(context.Background(), newUser)
+
+	var conflictErr *domain.ConflictError
+	suite.Require().ErrorAs(err, &conflictErr)
+	suite.Equal("email", conflictErr.Field)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Save_DuplicateID() {
+	newUser := suite.TestData.User
+	newUser.Email = "test-6@email.com"
+
+	_, err := suite.TestRepo.Save(context.Background(), newUser)
+
+	var conflictErr *domain.ConflictError
+	suite.Require().ErrorAs(err, &conflictErr)
+	suite.Equal("id", conflictErr.Field)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Save_DeletedID() {
+	suite.NoError(suite.TestRepo.Delete(context.Background(), suite.TestData.User.ID))
+
+	newUser := suite.TestData.User
+	newUser.Email = "test-6@email.com"
+
+	_, err := suite.TestRepo.Save(context.Background(), newUser)
+
+	var conflictErr *domain.ConflictError
+	suite.Require().ErrorAs(err, &conflictErr)
+	suite.Equal("id", conflictErr.Field)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Update() {
+	updated := suite.TestData.User
+	updated.Name = "test-name-3"
+	updated.Email = ""
+
+	result, err := suite.TestRepo.Update(context.Background(), updated)
+
+	suite.NoError(err)
+	suite.EqualValues(2, result.Version)
+
+	stored, err := suite.TestRepo.Get(context.Background(), updated.ID)
+
+	suite.NoError(err)
+	suite.Equal("test-name-3", stored.Name)
+	suite.Equal(suite.TestData.User.Email, stored.Email)
+	suite.EqualValues(2, stored.Version)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Update_StaleVersion() {
+	stale := suite.TestData.User
+	stale.Version = 2
+
+	_, err := suite.TestRepo.Update(context.Background(), stale)
+
+	suite.ErrorIs(err, domain.ErrPreconditionFailed)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Update_DuplicateEmail() {
+	suite.save("other-id", "other", "other@email.com")
+
+	updated := suite.TestData.User
+	updated.Email = "other@email.com"
+
+	_, err := suite.TestRepo.Update(context.Background(), updated)
+
+	var conflictErr *domain.ConflictError
+	suite.Require().ErrorAs(err, &conflictErr)
+	suite.Equal("email", conflictErr.Field)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_UpdateUnscoped_Deleted() {
+	suite.NoError(suite.TestRepo.Delete(context.Background(), suite.TestData.User.ID))
+
+	deleted, err := suite.TestRepo.GetUnscoped(context.Background(), suite.TestData.User.ID)
+
+	suite.NoError(err)
+	suite.True(deleted.DeletedAt.Valid)
+
+	deleted.Erase("admin-id", time.Now())
+
+	_, err = suite.TestRepo.Update(context.Background(), deleted)
+	suite.ErrorIs(err, domain.ErrPreconditionFailed)
+
+	result, err := suite.TestRepo.UpdateUnscoped(context.Background(), deleted)
+
+	suite.NoError(err)
+	suite.EqualValues(2, result.Version)
+
+	stored, err := suite.TestRepo.GetUnscoped(context.Background(), suite.TestData.User.ID)
+
+	suite.NoError(err)
+	suite.True(stored.IsErased())
+	suite.Equal(deleted.Email, stored.Email)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Delete() {
+	err := suite.TestRepo.Delete(context.Background(), suite.TestData.User.ID)
+
+	suite.NoError(err)
+
+	_, err = suite.TestRepo.Get(context.Background(), suite.TestData.User.ID)
+
+	suite.ErrorIs(err, domain.ErrNotFound)
+
+	err = suite.TestRepo.Delete(context.Background(), suite.TestData.User.ID)
+
+	suite.ErrorIs(err, domain.ErrNotFound)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Delete_ReleasesEmail() {
+	suite.NoError(suite.TestRepo.Delete(context.Background(), suite.TestData.User.ID))
+
+	suite.save("test-id-2", "test-name", suite.TestData.User.Email)
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Transaction_Rollback() {
+	err := suite.TestRepo.Transaction(context.Background(), func(ctx context.Context) error {
+		if _, err := suite.TestRepo.Save(ctx, domain.User{ID: "tx-id", Email: "tx@email.com"}); err != nil {
+			return err
+		}
+
+		suite.addMessage(ctx, "tx-id", time.Now().UTC())
+
+		return errors.New("rollback")
+	})
+
+	suite.Error(err)
+
+	_, err = suite.TestRepo.Get(context.Background(), "tx-id")
+	suite.ErrorIs(err, domain.ErrNotFound)
+
+	suite.Empty(suite.pending(context.Background(), time.Now().UTC(), 10))
+}
+
+func (suite *RepositoryContractTestSuite) TestRepository_Transaction_Commit() {
+	err := suite.TestRepo.Transaction(context.Background(), func(ctx context.Context) error {
+		if _, err := suite.TestRepo.Save(ctx, domain.User{ID: "tx-id", Email: "tx@email.com"}); err != nil {
+			return err
+		}
+
+		return suite.TestRepo.Transaction(ctx, func(ctx context.Context) error {
+			suite.addMessage(ctx, "tx-id", time.Now().UTC())
+			return nil
+		})
+	})
+
+	suite.NoError(err)
+
+	_, err = suite.TestRepo.Get(context.Background(), "tx-id")
+	suite.NoError(err)
+
+	pending := suite.pending(context.Background(), time.Now().UTC(), 10)
+	suite.Require().Len(pending, 1)
+	suite.Equal("tx-id", pending[0].AggregateID)
+}
+
+func (suite *RepositoryContractTestSuite) TestOutboxRepository_Claim() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	suite.addMessage(ctx, "first", now)
+	suite.addMessage(ctx, "first", now)
+	suite.addMessage(ctx, "second", now.Add(time.Minute))
+	suite.addMessage(ctx, "third", now)
+
+	pending := suite.pending(ctx, now, 10)
+
+	suite.Require().Len(pending, 2)
+	suite.Equal("first", pending[0].AggregateID)
+	suite.Equal("third", pending[1].AggregateID)
+
+	dead := pending[0].ID
+
+	suite.NoError(suite.TestOutboxRepo.MarkDead(ctx, dead, "unknown type", now))
+
+	pending = suite.pending(ctx, now, 10)
+
+	suite.Require().Len(pending, 2)
+	suite.Equal("first", pending[0].AggregateID)
+	suite.Greater(pending[0].ID, dead)
+	suite.Equal("third", pending[1].AggregateID)
+
+	suite.Len(suite.pending(ctx, now.Add(time.Minute), 10), 3)
+}
+
+func (suite *RepositoryContractTestSuite) TestOutboxRepository_Claim_Limit() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	suite.addMessage(ctx, "first", now)
+	suite.addMessage(ctx, "second", now)
+
+	pending := suite.pending(ctx, now, 1)
+
+	suite.Require().Len(pending, 1)
+	suite.Equal("first", pending[0].AggregateID)
+}
+
+func (suite *RepositoryContractTestSuite) TestOutboxRepository_Claim_SkipsClaimed() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	until := now.Add(time.Minute)
+
+	suite.addMessage(ctx, "first", now)
+	suite.addMessage(ctx, "first", now)
+
+	claimed, err := suite.TestOutboxRepo.Claim(ctx, now, until, 10)
+
+	suite.NoError(err)
+	suite.Require().Len(claimed, 1)
+	suite.Require().NotNil(claimed[0].ClaimedUntil)
+	suite.WithinDuration(until, *claimed[0].ClaimedUntil, time.Millisecond)
+
+	concurrent, err := suite.TestOutboxRepo.Claim(ctx, now, until, 10)
+
+	suite.NoError(err)
+	suite.Empty(concurrent, "claimed messages and the later messages of their user are skipped")
+
+	expired, err := suite.TestOutboxRepo.Claim(ctx, until, until.Add(time.Minute), 10)
+
+	suite.NoError(err)
+	suite.Require().Len(expired, 1)
+	suite.Equal(claimed[0].ID, expired[0].ID)
+}
+
+func (suite *RepositoryContractTestSuite) TestOutboxRepository_MarkFailed() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	suite.addMessage(ctx, "first", now)
+
+	claimed, err := suite.TestOutboxRepo.Claim(ctx, now, now.Add(time.Hour), 1)
+	suite.Require().NoError(err)
+	suite.Require().Len(claimed, 1)
+
+	next := now.Add(time.Minute)
+
+	suite.NoError(suite.TestOutboxRepo.MarkFailed(ctx, claimed[0].ID, "broker unavailable", next))
+
+	suite.Empty(suite.pending(ctx, now, 1))
+
+	result := suite.pending(ctx, next, 1)
+
+	suite.Require().Len(result, 1, "failing a message releases its claim")
+	suite.Equal(1, result[0].Attempts)
+	suite.Equal("broker unavailable", result[0].LastError)
+	suite.WithinDuration(next, result[0].NextAttemptAt, time.Millisecond)
+}
+
+func (suite *RepositoryContractTestSuite) TestOutboxRepository_Delete() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	suite.addMessage(ctx, "first", now)
+
+	pending := suite.pending(ctx, now, 1)
+	suite.Require().Len(pending, 1)
+
+	suite.NoError(suite.TestOutboxRepo.Delete(ctx, pending[0].ID))
+
+	suite.Empty(suite.pending(ctx, now, 1))
+}
+
+func (suite *RepositoryContractTestSuite) TestOutboxRepository_Scrub() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	user := suite.TestData.User
+
+	for _, eventType := range []string{domain.UserCreatedEvent, domain.UserUpdatedEvent, domain.UserDeletedEvent} {
+		message, err := domain.NewOutboxMessage(eventType, user, now)
+		suite.Require().NoError(err)
+		suite.Require().NoError(suite.TestOutboxRepo.Add(ctx, message))
+	}
+
+	suite.addMessage(ctx, "other-id", now)
+
+	pending := suite.pending(ctx, now, 1)
+	suite.Require().Len(pending, 1)
+	suite.Require().NoError(suite.TestOutboxRepo.MarkDead(ctx, pending[0].ID, "broker unavailable", now))
+
+	user.Erase("test-admin", now)
+
+	suite.NoError(suite.TestOutboxRepo.Scrub(ctx, user))
+
+	dead, err := suite.TestOutboxRepo.ListDead(ctx, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(dead, 1)
+
+	scrubbed, err := dead[0].User()
+
+	suite.NoError(err)
+	suite.Equal(user, scrubbed)
+
+	for i := 0; i < 2; i++ {
+		pending = suite.pending(ctx, now, 10)
+		suite.Require().Len(pending, 2)
+		suite.Equal("other-id", pending[1].AggregateID)
+
+		scrubbed, err = pending[0].User()
+
+		suite.NoError(err)
+		suite.Equal(user, scrubbed)
+
+		suite.Require().NoError(suite.TestOutboxRepo.Delete(ctx, pending[0].ID))
+	}
+}
+
+func (suite *RepositoryContractTestSuite) TestOutboxRepository_PurgeDead() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	suite.addMessage(ctx, "first", now)
+	suite.addMessage(ctx, "second", now)
+	suite.addMessage(ctx, "third", now)
+
+	pending := suite.pending(ctx, now, 10)
+	suite.Require().Len(pending, 3)
+
+	suite.NoError(suite.TestOutboxRepo.MarkDead(ctx, pending[0].ID, "broker unavailable", now.Add(-2*time.Hour)))
+	suite.NoError(suite.TestOutboxRepo.MarkDead(ctx, pending[1].ID, "broker unavailable", now))
+
+	purged, err := suite.TestOutboxRepo.PurgeDead(ctx, now.Add(-time.Hour))
+
+	suite.NoError(err)
+	suite.Equal(1, purged)
+
+	purged, err = suite.TestOutboxRepo.PurgeDead(ctx, now.Add(time.Second))
+
+	suite.NoError(err)
+	suite.Equal(1, purged)
+
+	remaining := suite.pending(ctx, now, 10)
+
+	suite.Require().Len(remaining, 1, "messages that aren't dead are kept")
+	suite.Equal("third", remaining[0].AggregateID)
+}
+
+func (suite *RepositoryContractTestSuite) TestOutboxRepository_DeadMessages() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	suite.addMessage(ctx, "first", now)
+	suite.addMessage(ctx, "second", now)
+
+	pending := suite.pending(ctx, now, 10)
+	suite.Require().Len(pending, 2)
+
+	_, err := suite.TestOutboxRepo.GetDead(ctx, pending[0].ID)
+	suite.ErrorIs(err, domain.ErrNotFound, "messages that aren't dead are not dead letters")
+
+	for _, message := range pending {
+		suite.NoError(suite.TestOutboxRepo.MarkDead(ctx, message.ID, "broker unavailable", now))
+	}
+
+	dead, err := suite.TestOutboxRepo.ListDead(ctx, 1)
+
+	suite.NoError(err)
+	suite.Require().Len(dead, 1)
+	suite.Equal(pending[0].ID, dead[0].ID)
+	suite.Equal("broker unavailable", dead[0].LastError)
+	suite.Equal(1, dead[0].Attempts)
+	suite.Require().NotNil(dead[0].DeadAt)
+
+	result, err := suite.TestOutboxRepo.GetDead(ctx, pending[1].ID)
+
+	suite.NoError(err)
+	suite.Equal("second", result.AggregateID)
+
+	suite.NoError(suite.TestOutboxRepo.DeleteDead(ctx, pending[1].ID))
+	suite.ErrorIs(suite.TestOutboxRepo.DeleteDead(ctx, pending[1].ID), domain.ErrNotFound)
+}
+
+func (suite *RepositoryContractTestSuite) TestOutboxRepository_Revive() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	suite.addMessage(ctx, "first", now)
+
+	pending := suite.pending(ctx, now, 1)
+	suite.Require().Len(pending, 1)
+
+	suite.ErrorIs(suite.TestOutboxRepo.Revive(ctx, pending[0].ID, now), domain.ErrNotFound)
+	suite.NoError(suite.TestOutboxRepo.MarkDead(ctx, pending[0].ID, "broker unavailable", now))
+	suite.Empty(suite.pending(ctx, now, 1))
+
+	later := now.Add(time.Minute)
+
+	suite.NoError(suite.TestOutboxRepo.Revive(ctx, pending[0].ID, later))
+	suite.Empty(suite.pending(ctx, now, 1))
+
+	revived := suite.pending(ctx, later, 1)
+
+	suite.Require().Len(revived, 1)
+	suite.Equal(pending[0].ID, revived[0].ID)
+	suite.Equal(0, revived[0].Attempts)
+	suite.Empty(revived[0].LastError)
+	suite.Nil(revived[0].DeadAt)
+
+	dead, err := suite.TestOutboxRepo.ListDead(ctx, 10)
+
+	suite.NoError(err)
+	suite.Empty(dead)
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"path/filepath"
+	"testing"
+	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
+)
+
+type SQLiteUserRepositoryTestSuite struct {
+	suite.Suite
+	TestDb   *gorm.DB
+	TestRepo *userRepository
+	TestData struct {
+		User domain.User
+	}
+}
+
+// openSQLite opens a new database in a temporary file, which is closed at the end of the test.
+func openSQLite(t *testing.T) *gorm.DB {
+	dsn := "file:" + filepath.Join(t.TempDir(), "user.db") + "?_pragma=busy_timeout(5000)"
+	db, err := gorm.Open(sqlite.Open(dsn))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	return db
+}
+
+func (suite *SQLiteUserRepositoryTestSuite) SetupTest() {
+	db := openSQLite(suite.T())
+
+	repository, err := NewUserRepository(db)
+	suite.Require().NoError(err)
+
+	suite.TestDb = db
+	suite.TestRepo = repository
+	suite.TestData = struct {
+		User domain.User
+	}{
+		User: domain.User{
+			ID:       "test-id",
+			Name:     "test-name",
+			LastName: "test-lastname",
+			Email:    "test@email.com",
+			Version:  1,
+		},
+	}
+
+	_, err = suite.TestRepo.Save(context.Background(), suite.TestData.User)
+	suite.Require().NoError(err)
+}
+
+func (suite *SQLiteUserRepositoryTestSuite) save(id, name, email string) {
+	_, err := suite.TestRepo.Save(context.Background(), domain.User{ID: id, Name: name, LastName: "test-lastname", Email: email})
+	suite.Require().NoError(err)
+}
+
+func (suite *SQLiteUserRepositoryTestSuite) TestRepository_Search() {
+	suite.save("search-id-1", "johanna", "johanna@email.com")
+	suite.save("search-id-2", "john", "john@email.com")
+	suite.save("search-id-3", "mary-johan", "mary@email.com")
+	suite.save("search-id-4", "johan", "johan@email.com")
+
+	page, _ := domain.NewSearchPage(1, 10)
+
+	result, err := suite.TestRepo.Search(context.Background(), "Johan", page)
+
+	suite.NoError(err)
+
+	suite.EqualValues(3, result.Total)
+	suite.Require().Len(result.Users, 3)
+	suite.Equal("search-id-4", result.Users[0].ID)
+	suite.Equal("search-id-1", result.Users[1].ID)
+	suite.Equal("search-id-3", result.Users[2].ID)
+}
+
+func (suite *SQLiteUserRepositoryTestSuite) TestRepository_Search_EscapesWildcards() {
+	page, _ := domain.NewSearchPage(1, 10)
+
+	result, err := suite.TestRepo.Search(context.Background(), "test_", page)
+
+	suite.NoError(err)
+	suite.EqualValues(0, result.Total)
+}
+
+func (suite *SQLiteUserRepositoryTestSuite) TestRepository_Migrate_DuplicateEmails() {
+	suite.TestDb.Exec("DROP INDEX idx_users_email_lower")
+	suite.TestDb.Exec("INSERT INTO users (id, name, last_name, email) VALUES ('test-id-2', 'test-name', 'test-lastname', 'TEST@email.com')")
+	suite.save("other-id", "other", "other@email.com")
+
+	_, err := NewUserRepository(suite.TestDb)
+
+	suite.Error(err)
+	suite.Contains(err.Error(), "(test@email.com: test-id test-id-2)")
+}
+
+func TestUnit_SQLiteUserRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SQLiteUserRepositoryTestSuite))
+}
+
+func TestUnit_SQLiteRepositoryContractTestSuite(t *testing.T) {
+	suite.Run(t, newRepositoryContractTestSuite(func(t *testing.T) (interfaces.UserRepository, interfaces.OutboxRepository) {
+		db := openSQLite(t)
+
+		userRepository, err := NewUserRepository(db)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		outboxRepository, err := NewOutboxRepository(db)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return userRepository, outboxRepository
+	}))
+}
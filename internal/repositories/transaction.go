@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type transactionKey struct{}
+
+// transaction runs fn in a database transaction that is carried in the context,
+// so every repository called with that context takes part in it. Nested calls
+// join the outer transaction.
+func transaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, transactionKey{}, tx))
+	})
+}
+
+func connection(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
@@ -2,14 +2,25 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"strings"
+	"time"
 	"user-service/internal/core/domain"
 )
 
+var likeEscaper = strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+
+// sqliteTimestampFormat is the text format of CURRENT_TIMESTAMP in SQLite,
+// which stores the creation times of users as UTC text.
+const sqliteTimestampFormat = "2006-01-02 15:04:05"
+
 type userRepository struct {
 	Connection *gorm.DB
+	trigram    bool
 }
 
 func NewUserRepository(db *gorm.DB) (*userRepository, error) {
@@ -19,49 +30,292 @@ func NewUserRepository(db *gorm.DB) (*userRepository, error) {
 		return nil, err
 	}
 
+	err = migrateIndexes(db)
+
+	if err != nil {
+		return nil, err
+	}
+
+	trigram, err := migrateTrigramIndexes(db)
+
+	if err != nil {
+		return nil, err
+	}
+
 	database := userRepository{
 		Connection: db,
+		trigram:    trigram,
 	}
 
 	return &database, nil
 }
 
 func (repository *userRepository) Get(ctx context.Context, id string) (domain.User, error) {
+	return repository.get(connection(ctx, repository.Connection), id)
+}
+
+func (repository *userRepository) GetUnscoped(ctx context.Context, id string) (domain.User, error) {
+	return repository.get(connection(ctx, repository.Connection).Unscoped(), id)
+}
+
+func (repository *userRepository) get(db *gorm.DB, id string) (domain.User, error) {
 	var user domain.User
 
-	repository.Connection.WithContext(ctx).Preload(clause.Associations).First(&user, "id = ?", id)
+	result := db.Preload(clause.Associations).First(&user, "id = ?", id)
 
-	if (user == domain.User{}) {
-		return user, errors.New("user not found")
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return domain.User{}, domain.NewNotFoundError("user", id)
+	}
+
+	if result.Error != nil {
+		return domain.User{}, result.Error
 	}
 
 	return user, nil
 }
 
-func (repository *userRepository) GetAll(ctx context.Context) ([]domain.User, error) {
+func (repository *userRepository) GetAll(ctx context.Context, query domain.UserQuery) (domain.UserPage, error) {
+	db := connection(ctx, repository.Connection).Model(&domain.User{})
+
+	if query.Email != "" {
+		db = db.Where("email = ?", query.Email)
+	}
+
+	if query.NamePrefix != "" {
+		db = db.Where("name LIKE ? ESCAPE '\\'", likeEscaper.Replace(query.NamePrefix)+"%")
+	}
+
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+
+	if err := db.Count(&total).Error; err != nil {
+		return domain.UserPage{}, err
+	}
+
+	direction, comparison := "ASC", ">"
+
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	page := db.Order(fmt.Sprintf("%s %s, id %s", query.Sort, direction, direction)).Limit(query.Limit + 1)
+
+	if query.Cursor != nil {
+		value, err := query.CursorValue()
+
+		if err != nil {
+			return domain.UserPage{}, err
+		}
+
+		if createdAt, ok := value.(time.Time); ok && isSQLite(repository.Connection) {
+			value = createdAt.UTC().Format(sqliteTimestampFormat)
+		}
+
+		page = page.Where(fmt.Sprintf("(%s, id) %s (?, ?)", query.Sort, comparison), value, query.Cursor.ID)
+	}
+
+	var users []domain.User
+
+	if err := page.Find(&users).Error; err != nil {
+		return domain.UserPage{}, err
+	}
+
+	result := domain.UserPage{Users: users, Total: total}
+
+	if len(users) > query.Limit {
+		result.Users = users[:query.Limit]
+		result.NextCursor = domain.EncodeUserCursor(result.Users[query.Limit-1], query.Sort)
+	}
+
+	return result, nil
+}
+
+// Search ranks users by trigram word similarity. Without pg_trgm, as on SQLite,
+// it only matches case-insensitive substrings and ranks exact matches before
+// prefix matches before other matches.
+func (repository *userRepository) Search(ctx context.Context, query string, page domain.SearchPage) (domain.UserSearchResult, error) {
+	if !repository.trigram {
+		return repository.searchSubstring(ctx, query, page)
+	}
+
+	pattern := "%" + likeEscaper.Replace(query) + "%"
+
+	db := connection(ctx, repository.Connection).Model(&domain.User{}).
+		Where("name ILIKE @pattern OR last_name ILIKE @pattern OR email ILIKE @pattern OR "+
+			"@query <% name OR @query <% last_name OR @query <% email",
+			sql.Named("pattern", pattern), sql.Named("query", query)).
+		Session(&gorm.Session{})
+
+	var total int64
+
+	if err := db.Count(&total).Error; err != nil {
+		return domain.UserSearchResult{}, err
+	}
+
+	var users []domain.User
+
+	err := db.Clauses(clause.OrderBy{Expression: clause.Expr{
+		SQL:                "GREATEST(word_similarity(?, name), word_similarity(?, last_name), word_similarity(?, email)) DESC, id",
+		Vars:               []interface{}{query, query, query},
+		WithoutParentheses: true,
+	}}).Offset(page.Offset()).Limit(page.Size).Find(&users).Error
+
+	if err != nil {
+		return domain.UserSearchResult{}, err
+	}
+
+	return domain.UserSearchResult{Users: users, Total: total}, nil
+}
+
+func (repository *userRepository) searchSubstring(ctx context.Context, query string, page domain.SearchPage) (domain.UserSearchResult, error) {
+	query = strings.ToLower(query)
+	escaped := likeEscaper.Replace(query)
+
+	db := connection(ctx, repository.Connection).Model(&domain.User{}).
+		Where("LOWER(name) LIKE @pattern ESCAPE '\\' OR LOWER(last_name) LIKE @pattern ESCAPE '\\' OR LOWER(email) LIKE @pattern ESCAPE '\\'",
+			sql.Named("pattern", "%"+escaped+"%")).
+		Session(&gorm.Session{})
+
+	var total int64
+
+	if err := db.Count(&total).Error; err != nil {
+		return domain.UserSearchResult{}, err
+	}
+
 	var users []domain.User
 
-	repository.Connection.WithContext(ctx).Find(&users)
+	err := db.Clauses(clause.OrderBy{Expression: clause.NamedExpr{
+		SQL: "CASE WHEN LOWER(name) = @query OR LOWER(last_name) = @query OR LOWER(email) = @query THEN 0 " +
+			"WHEN LOWER(name) LIKE @prefix ESCAPE '\\' OR LOWER(last_name) LIKE @prefix ESCAPE '\\' OR LOWER(email) LIKE @prefix ESCAPE '\\' THEN 1 " +
+			"ELSE 2 END, id",
+		Vars: []interface{}{sql.Named("query", query), sql.Named("prefix", escaped+"%")},
+	}}).Offset(page.Offset()).Limit(page.Size).Find(&users).Error
 
-	return users, nil
+	if err != nil {
+		return domain.UserSearchResult{}, err
+	}
+
+	return domain.UserSearchResult{Users: users, Total: total}, nil
 }
 
 func (repository *userRepository) Save(ctx context.Context, user domain.User) (domain.User, error) {
-	result := repository.Connection.WithContext(ctx).Create(&user)
+	result := connection(ctx, repository.Connection).Create(&user)
 
 	if result.Error != nil {
-		return domain.User{}, result.Error
+		return domain.User{}, translateError(result.Error)
 	}
 
 	return user, nil
 }
 
 func (repository *userRepository) Update(ctx context.Context, user domain.User) (domain.User, error) {
-	result := repository.Connection.WithContext(ctx).Model(&user).Updates(user)
+	return repository.update(connection(ctx, repository.Connection), user)
+}
+
+func (repository *userRepository) UpdateUnscoped(ctx context.Context, user domain.User) (domain.User, error) {
+	return repository.update(connection(ctx, repository.Connection).Unscoped(), user)
+}
+
+func (repository *userRepository) update(db *gorm.DB, user domain.User) (domain.User, error) {
+	expected := user.Version
+	user.Version++
+
+	result := db.Model(&user).Where("version = ?", expected).Updates(user)
 
 	if result.Error != nil {
-		return domain.User{}, result.Error
+		return domain.User{}, translateError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.User{}, domain.NewPreconditionFailedError("user has been modified")
 	}
 
 	return user, nil
 }
+
+func (repository *userRepository) Delete(ctx context.Context, id string) error {
+	result := connection(ctx, repository.Connection).Delete(&domain.User{}, "id = ?", id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.NewNotFoundError("user", id)
+	}
+
+	return nil
+}
+
+func (repository *userRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return transaction(ctx, repository.Connection, fn)
+}
+
+// migrateIndexes creates the indexes GORM cannot declare. Emails used to be
+// unique regardless of case only, so the unique index is not created while
+// users share an email; those users are reported in the error instead.
+func migrateIndexes(db *gorm.DB) error {
+	var duplicates []struct {
+		ID    string
+		Email string
+	}
+
+	err := db.Raw("SELECT id, LOWER(email) AS email FROM users WHERE deleted_at IS NULL AND LOWER(email) IN " +
+		"(SELECT LOWER(email) FROM users WHERE deleted_at IS NULL GROUP BY LOWER(email) HAVING COUNT(*) > 1) " +
+		"ORDER BY LOWER(email), id").Scan(&duplicates).Error
+
+	if err != nil {
+		return err
+	}
+
+	if len(duplicates) > 0 {
+		var conflicts []string
+
+		for i, duplicate := range duplicates {
+			if i == 0 || duplicates[i-1].Email != duplicate.Email {
+				conflicts = append(conflicts, duplicate.Email+":")
+			}
+
+			conflicts[len(conflicts)-1] += " " + duplicate.ID
+		}
+
+		return fmt.Errorf("cannot create unique index idx_users_email_lower, users share an email regardless of case "+
+			"(%s); change or delete all but one of them", strings.Join(conflicts, "; "))
+	}
+
+	return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email)) WHERE deleted_at IS NULL").Error
+}
+
+// migrateTrigramIndexes creates the trigram indexes used by Search and reports
+// whether they exist. Creating the pg_trgm extension needs privileges the
+// database role may not have, and SQLite has no extensions, so their absence
+// only degrades the search.
+func migrateTrigramIndexes(db *gorm.DB) (bool, error) {
+	if isSQLite(db) {
+		return false, nil
+	}
+
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		db.Logger.Warn(context.Background(), "pg_trgm is not available, search only matches substrings: %v", err)
+		return false, nil
+	}
+
+	statements := []string{
+		"CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING gin (name gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_users_last_name_trgm ON users USING gin (last_name gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING gin (email gin_trgm_ops)",
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func isSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == "sqlite"
+}
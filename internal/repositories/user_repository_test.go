@@ -8,38 +8,45 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"testing"
+	"time"
 	"user-service/config"
 	"user-service/internal/core/domain"
+	"user-service/internal/core/interfaces"
 )
 
 type UserRepositoryTestSuite struct {
 	suite.Suite
 	TestDb   *gorm.DB
 	TestRepo *userRepository
-	Cfg      *config.Config
 	TestData struct {
 		User domain.User
 	}
 }
 
-func (suite *UserRepositoryTestSuite) SetupSuite() {
-	cfgPath := "../../test/user.config"
-	cfg, err := config.UseConfig(cfgPath)
+// openPostgres connects to the database of the integration tests.
+func openPostgres() (*gorm.DB, error) {
+	cfg, err := config.UseConfig("../../test/user.config")
 
 	if err != nil {
-		panic(errors.WithStack(err))
+		return nil, err
 	}
 
 	dsn := fmt.Sprintf("host=%s port=%d user=%s "+
 		"password=%s dbname=%s sslmode=disable",
 		cfg.Database.Host, cfg.Database.Port, cfg.Database.User, cfg.Database.Password, cfg.Database.Database)
-	db, err := gorm.Open(postgres.Open(dsn))
-	db.Debug()
+
+	return gorm.Open(postgres.Open(dsn))
+}
+
+func (suite *UserRepositoryTestSuite) SetupSuite() {
+	db, err := openPostgres()
 
 	if err != nil {
 		panic(errors.WithStack(err))
 	}
 
+	db.Debug()
+
 	repository, err := NewUserRepository(db)
 
 	if err != nil {
@@ -50,7 +57,6 @@ func (suite *UserRepositoryTestSuite) SetupSuite() {
 
 	db.Exec("INSERT INTO public.users (id, name, last_name, email) VALUES ('test-id', 'test-name', 'test-lastname', 'test@email.com')")
 
-	suite.Cfg = cfg
 	suite.TestDb = db
 	suite.TestRepo = repository
 	suite.TestData = struct {
@@ -61,28 +67,41 @@ func (suite *UserRepositoryTestSuite) SetupSuite() {
 			Name:     "test-name",
 			LastName: "test-lastname",
 			Email:    "test@email.com",
+			Version:  1,
 		},
 	}
 }
 
-func (suite *UserRepositoryTestSuite) TestRepository_Get() {
-	result, err := suite.TestRepo.Get(context.Background(), suite.TestData.User.ID)
+func (suite *UserRepositoryTestSuite) TestRepository_GetAll() {
+	query, _ := domain.NewUserQuery(0, "", "", "", "")
+
+	result, err := suite.TestRepo.GetAll(context.Background(), query)
 
 	suite.NoError(err)
 
-	suite.EqualValues(suite.TestData.User, result)
+	suite.GreaterOrEqual(result.Total, int64(1))
+	suite.NotEmpty(result.Users)
 }
 
-func (suite *UserRepositoryTestSuite) TestRepository_Get_NotFound() {
-	_, err := suite.TestRepo.Get(context.Background(), "test")
+func (suite *UserRepositoryTestSuite) TestRepository_Search() {
+	suite.TestDb.Exec("INSERT INTO public.users (id, name, last_name, email) VALUES ('search-id-1', 'johanna', 'searchable', 'johanna@email.com')")
+	suite.TestDb.Exec("INSERT INTO public.users (id, name, last_name, email) VALUES ('search-id-2', 'john', 'searchable', 'john@email.com')")
 
-	suite.Error(err)
+	page, _ := domain.NewSearchPage(1, 10)
+
+	result, err := suite.TestRepo.Search(context.Background(), "johan", page)
+
+	suite.NoError(err)
+
+	suite.NotEmpty(result.Users)
+	suite.Equal("search-id-1", result.Users[0].ID)
 }
 
 func (suite *UserRepositoryTestSuite) TestRepository_Save() {
 	newUser := suite.TestData.User
 	newUser.Name = "test-name-3"
 	newUser.ID = "test-id-3"
+	newUser.Email = "test-3@email.com"
 
 	_, err := suite.TestRepo.Save(context.Background(), newUser)
 
@@ -96,11 +115,12 @@ func (suite *UserRepositoryTestSuite) TestRepository_Save() {
 }
 
 func (suite *UserRepositoryTestSuite) TestRepository_Update() {
-	suite.TestDb.Exec("INSERT INTO public.users (id, name, last_name, email) VALUES ('test-id-2', 'test-name', 'test-lastname', 'test@email.com')")
+	suite.TestDb.Exec("INSERT INTO public.users (id, name, last_name, email) VALUES ('test-id-2', 'test-name', 'test-lastname', 'test-2@email.com')")
 
 	updated := suite.TestData.User
 	updated.ID = "test-id-2"
 	updated.Name = "test-name-3"
+	updated.Email = "test-2@email.com"
 
 	_, err := suite.TestRepo.Update(context.Background(), updated)
 
@@ -113,7 +133,67 @@ func (suite *UserRepositoryTestSuite) TestRepository_Update() {
 	suite.EqualValues(updated.Name, queryResult.Name)
 }
 
+func (suite *UserRepositoryTestSuite) TestRepository_Update_IncrementsVersion() {
+	suite.TestDb.Exec("INSERT INTO public.users (id, name, last_name, email) VALUES ('test-id-7', 'test-name', 'test-lastname', 'test-7@email.com')")
+
+	existing, err := suite.TestRepo.Get(context.Background(), "test-id-7")
+
+	suite.NoError(err)
+	suite.EqualValues(1, existing.Version)
+
+	existing.Name = "test-name-7"
+
+	updated, err := suite.TestRepo.Update(context.Background(), existing)
+
+	suite.NoError(err)
+	suite.EqualValues(2, updated.Version)
+}
+
+func (suite *UserRepositoryTestSuite) TestRepository_Delete() {
+	suite.TestDb.Exec("INSERT INTO public.users (id, name, last_name, email) VALUES ('test-id-4', 'test-name', 'test-lastname', 'test-4@email.com')")
+
+	err := suite.TestRepo.Delete(context.Background(), "test-id-4")
+
+	suite.NoError(err)
+
+	_, err = suite.TestRepo.Get(context.Background(), "test-id-4")
+
+	suite.Error(err)
+
+	var deletedAt *time.Time
+	suite.TestDb.Raw("SELECT deleted_at FROM public.users WHERE id=?", "test-id-4").Scan(&deletedAt)
+
+	suite.NotNil(deletedAt)
+}
+
 func TestIntegration_UserRepositoryTestSuite(t *testing.T) {
 	testSuite := new(UserRepositoryTestSuite)
 	suite.Run(t, testSuite)
 }
+
+func TestIntegration_PostgresRepositoryContractTestSuite(t *testing.T) {
+	db, err := openPostgres()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userRepository, err := NewUserRepository(db)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outboxRepository, err := NewOutboxRepository(db)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	suite.Run(t, newRepositoryContractTestSuite(func(t *testing.T) (interfaces.UserRepository, interfaces.OutboxRepository) {
+		db.Exec("DELETE FROM public.outbox_messages")
+		db.Exec("DELETE FROM public.users")
+
+		return userRepository, outboxRepository
+	}))
+}
@@ -38,3 +38,7 @@ func (auth *RestAuthorization) AuthorizeAdmin() bool {
 func (auth *RestAuthorization) AuthorizeMatchingId(id string) bool {
 	return auth.id == id
 }
+
+func (auth *RestAuthorization) Id() string {
+	return auth.id
+}
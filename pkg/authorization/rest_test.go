@@ -72,6 +72,7 @@ func (suite *AuthorizationTestSuite) TestAuthorization_AuthorizeMatchingId() {
 	suite.Equal(userId, sut.id)
 
 	suite.True(sut.AuthorizeMatchingId(userId))
+	suite.Equal(userId, sut.Id())
 }
 
 func (suite *AuthorizationTestSuite) TestAuthorization_AuthorizeMatchingId_NoMatch() {
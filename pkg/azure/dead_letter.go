@@ -0,0 +1,179 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+)
+
+const (
+	deadLetterBatchSize = 100
+
+	// receiveTimeout is how long to wait for more dead letters before the dead
+	// letter queue is considered empty.
+	receiveTimeout = 5 * time.Second
+)
+
+var ErrDeadLetterNotFound = errors.New("azure: dead letter not found")
+
+type receiver interface {
+	PeekMessages(ctx context.Context, maxMessageCount int, options *azservicebus.PeekMessagesOptions) ([]*azservicebus.ReceivedMessage, error)
+	ReceiveMessages(ctx context.Context, maxMessages int, options *azservicebus.ReceiveMessagesOptions) ([]*azservicebus.ReceivedMessage, error)
+	CompleteMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.CompleteMessageOptions) error
+	AbandonMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.AbandonMessageOptions) error
+	Close(ctx context.Context) error
+}
+
+// PeekDeadLetters returns up to limit messages of the dead letter queue of the
+// subscription without removing or locking them.
+func (r *ServiceBus) PeekDeadLetters(ctx context.Context, topic, subscription string, limit int) ([]*azservicebus.ReceivedMessage, error) {
+	dlq, err := r.deadLetterReceiver(topic, subscription, azservicebus.ReceiveModePeekLock)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer dlq.Close(ctx)
+
+	return dlq.PeekMessages(ctx, limit, nil)
+}
+
+// FindDeadLetter returns the message with messageID from the dead letter queue
+// of the subscription without removing or locking it.
+func (r *ServiceBus) FindDeadLetter(ctx context.Context, topic, subscription, messageID string) (*azservicebus.ReceivedMessage, error) {
+	dlq, err := r.deadLetterReceiver(topic, subscription, azservicebus.ReceiveModePeekLock)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer dlq.Close(ctx)
+
+	for {
+		messages, err := dlq.PeekMessages(ctx, deadLetterBatchSize, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(messages) == 0 {
+			return nil, ErrDeadLetterNotFound
+		}
+
+		for _, message := range messages {
+			if message.MessageID == messageID {
+				return message, nil
+			}
+		}
+	}
+}
+
+// TakeDeadLetter calls fn with the message with messageID from the dead letter
+// queue of the subscription, and removes it when fn succeeds. Other messages
+// that were locked while searching are abandoned.
+func (r *ServiceBus) TakeDeadLetter(ctx context.Context, topic, subscription, messageID string, fn func(message *azservicebus.ReceivedMessage) error) error {
+	dlq, err := r.deadLetterReceiver(topic, subscription, azservicebus.ReceiveModePeekLock)
+
+	if err != nil {
+		return err
+	}
+
+	defer dlq.Close(ctx)
+
+	var locked []*azservicebus.ReceivedMessage
+
+	defer func() {
+		for _, message := range locked {
+			_ = dlq.AbandonMessage(ctx, message, nil)
+		}
+	}()
+
+	for {
+		messages, err := receiveBatch(ctx, dlq)
+
+		if err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return ErrDeadLetterNotFound
+		}
+
+		for i, message := range messages {
+			if message.MessageID != messageID {
+				continue
+			}
+
+			locked = append(locked, messages[:i]...)
+			locked = append(locked, messages[i+1:]...)
+
+			if err = fn(message); err != nil {
+				locked = append(locked, message)
+				return err
+			}
+
+			return dlq.CompleteMessage(ctx, message, nil)
+		}
+
+		locked = append(locked, messages...)
+	}
+}
+
+// PurgeDeadLetters removes every message from the dead letter queue of the
+// subscription.
+func (r *ServiceBus) PurgeDeadLetters(ctx context.Context, topic, subscription string) (int, error) {
+	dlq, err := r.deadLetterReceiver(topic, subscription, azservicebus.ReceiveModeReceiveAndDelete)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer dlq.Close(ctx)
+
+	purged := 0
+
+	for {
+		messages, err := receiveBatch(ctx, dlq)
+
+		if err != nil {
+			return purged, err
+		}
+
+		if len(messages) == 0 {
+			return purged, nil
+		}
+
+		purged += len(messages)
+	}
+}
+
+func (r *ServiceBus) deadLetterReceiver(topic, subscription string, mode azservicebus.ReceiveMode) (receiver, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return nil, ErrClosed
+	}
+
+	return r.newReceiver(topic, subscription, &azservicebus.ReceiverOptions{
+		ReceiveMode: mode,
+		SubQueue:    azservicebus.SubQueueDeadLetter,
+	})
+}
+
+// receiveBatch receives the next batch of messages, or none once no message
+// arrived within receiveTimeout.
+func receiveBatch(ctx context.Context, dlq receiver) ([]*azservicebus.ReceivedMessage, error) {
+	receiveCtx, cancel := context.WithTimeout(ctx, receiveTimeout)
+	defer cancel()
+
+	messages, err := dlq.ReceiveMessages(receiveCtx, deadLetterBatchSize, nil)
+
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, nil
+	}
+
+	return messages, err
+}
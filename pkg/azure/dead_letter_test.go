@@ -0,0 +1,154 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+)
+
+type fakeReceiver struct {
+	messages  []*azservicebus.ReceivedMessage
+	peeked    bool
+	received  bool
+	completed []string
+	abandoned []string
+	closed    bool
+}
+
+func (r *fakeReceiver) PeekMessages(ctx context.Context, maxMessageCount int, options *azservicebus.PeekMessagesOptions) ([]*azservicebus.ReceivedMessage, error) {
+	if r.peeked {
+		return nil, nil
+	}
+
+	r.peeked = true
+
+	if maxMessageCount < len(r.messages) {
+		return r.messages[:maxMessageCount], nil
+	}
+
+	return r.messages, nil
+}
+
+func (r *fakeReceiver) ReceiveMessages(ctx context.Context, maxMessages int, options *azservicebus.ReceiveMessagesOptions) ([]*azservicebus.ReceivedMessage, error) {
+	if r.received {
+		return nil, context.DeadlineExceeded
+	}
+
+	r.received = true
+
+	return r.messages, nil
+}
+
+func (r *fakeReceiver) CompleteMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.CompleteMessageOptions) error {
+	r.completed = append(r.completed, message.MessageID)
+	return nil
+}
+
+func (r *fakeReceiver) AbandonMessage(ctx context.Context, message *azservicebus.ReceivedMessage, options *azservicebus.AbandonMessageOptions) error {
+	r.abandoned = append(r.abandoned, message.MessageID)
+	return nil
+}
+
+func (r *fakeReceiver) Close(ctx context.Context) error {
+	r.closed = true
+	return nil
+}
+
+type DeadLetterTestSuite struct {
+	suite.Suite
+	TestServiceBus *ServiceBus
+	receiver       *fakeReceiver
+	options        *azservicebus.ReceiverOptions
+}
+
+func (suite *DeadLetterTestSuite) SetupTest() {
+	suite.receiver = &fakeReceiver{
+		messages: []*azservicebus.ReceivedMessage{{MessageID: "first"}, {MessageID: "second"}, {MessageID: "third"}},
+	}
+	suite.options = nil
+	suite.TestServiceBus = &ServiceBus{
+		newReceiver: func(topic, subscription string, options *azservicebus.ReceiverOptions) (receiver, error) {
+			suite.options = options
+			return suite.receiver, nil
+		},
+		senders: make(map[string]sender),
+	}
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_Peek() {
+	messages, err := suite.TestServiceBus.PeekDeadLetters(context.Background(), "topic", "subscription", 2)
+
+	suite.NoError(err)
+	suite.Len(messages, 2)
+	suite.Equal(azservicebus.SubQueueDeadLetter, suite.options.SubQueue)
+	suite.True(suite.receiver.closed)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_Find() {
+	message, err := suite.TestServiceBus.FindDeadLetter(context.Background(), "topic", "subscription", "second")
+
+	suite.NoError(err)
+	suite.Equal("second", message.MessageID)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_Find_NotFound() {
+	_, err := suite.TestServiceBus.FindDeadLetter(context.Background(), "topic", "subscription", "unknown")
+
+	suite.ErrorIs(err, ErrDeadLetterNotFound)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_Take() {
+	var taken string
+
+	err := suite.TestServiceBus.TakeDeadLetter(context.Background(), "topic", "subscription", "second", func(message *azservicebus.ReceivedMessage) error {
+		taken = message.MessageID
+		return nil
+	})
+
+	suite.NoError(err)
+	suite.Equal("second", taken)
+	suite.Equal([]string{"second"}, suite.receiver.completed)
+	suite.ElementsMatch([]string{"first", "third"}, suite.receiver.abandoned)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_Take_Failed() {
+	err := suite.TestServiceBus.TakeDeadLetter(context.Background(), "topic", "subscription", "second", func(message *azservicebus.ReceivedMessage) error {
+		return errors.New("handling failed")
+	})
+
+	suite.Error(err)
+	suite.Empty(suite.receiver.completed)
+	suite.ElementsMatch([]string{"first", "second", "third"}, suite.receiver.abandoned)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_Take_NotFound() {
+	err := suite.TestServiceBus.TakeDeadLetter(context.Background(), "topic", "subscription", "unknown", func(message *azservicebus.ReceivedMessage) error {
+		return nil
+	})
+
+	suite.ErrorIs(err, ErrDeadLetterNotFound)
+	suite.Len(suite.receiver.abandoned, 3)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_Purge() {
+	purged, err := suite.TestServiceBus.PurgeDeadLetters(context.Background(), "topic", "subscription")
+
+	suite.NoError(err)
+	suite.Equal(3, purged)
+	suite.Equal(azservicebus.ReceiveModeReceiveAndDelete, suite.options.ReceiveMode)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetter_Closed() {
+	suite.TestServiceBus.Close()
+
+	_, err := suite.TestServiceBus.PeekDeadLetters(context.Background(), "topic", "subscription", 1)
+
+	suite.ErrorIs(err, ErrClosed)
+}
+
+func TestUnit_DeadLetterTestSuite(t *testing.T) {
+	suite.Run(t, new(DeadLetterTestSuite))
+}
@@ -2,13 +2,32 @@ package azure
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"time"
 	"user-service/config"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
 )
 
+const closeTimeout = 10 * time.Second
+
+var ErrClosed = errors.New("azure: service bus closed")
+
+type sender interface {
+	SendMessage(ctx context.Context, message *azservicebus.Message, options *azservicebus.SendMessageOptions) error
+	Close(ctx context.Context) error
+}
+
+// ServiceBus keeps one long-lived sender per topic, senders are created on
+// first use and closed together with the client. It is safe for concurrent use.
 type ServiceBus struct {
-	Client *azservicebus.Client
+	Client      *azservicebus.Client
+	newSender   func(topic string) (sender, error)
+	newReceiver func(topic, subscription string, options *azservicebus.ReceiverOptions) (receiver, error)
+	mutex       sync.Mutex
+	senders     map[string]sender
+	closed      bool
 }
 
 func NewAzureServiceBus(cfg *config.Config) (*ServiceBus, error) {
@@ -22,9 +41,69 @@ func NewAzureServiceBus(cfg *config.Config) (*ServiceBus, error) {
 
 	return &ServiceBus{
 		Client: client,
+		newSender: func(topic string) (sender, error) {
+			return client.NewSender(topic, nil)
+		},
+		newReceiver: func(topic, subscription string, options *azservicebus.ReceiverOptions) (receiver, error) {
+			return client.NewReceiverForSubscription(topic, subscription, options)
+		},
+		senders: make(map[string]sender),
 	}, nil
 }
 
+func (r *ServiceBus) SendMessage(ctx context.Context, topic string, message *azservicebus.Message) error {
+	s, err := r.sender(topic)
+
+	if err != nil {
+		return err
+	}
+
+	return s.SendMessage(ctx, message, nil)
+}
+
+func (r *ServiceBus) sender(topic string) (sender, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return nil, ErrClosed
+	}
+
+	if s, ok := r.senders[topic]; ok {
+		return s, nil
+	}
+
+	s, err := r.newSender(topic)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.senders[topic] = s
+
+	return s, nil
+}
+
+// Close closes every cached sender and then the client.
 func (r *ServiceBus) Close() {
-	_ = r.Client.Close(context.Background())
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	r.closed = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+
+	for topic, s := range r.senders {
+		_ = s.Close(ctx)
+		delete(r.senders, topic)
+	}
+
+	if r.Client != nil {
+		_ = r.Client.Close(ctx)
+	}
 }
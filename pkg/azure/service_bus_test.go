@@ -0,0 +1,121 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+)
+
+type fakeSender struct {
+	mutex  sync.Mutex
+	sent   []*azservicebus.Message
+	closed bool
+}
+
+func (s *fakeSender) SendMessage(ctx context.Context, message *azservicebus.Message, options *azservicebus.SendMessageOptions) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sent = append(s.sent, message)
+
+	return nil
+}
+
+func (s *fakeSender) Close(ctx context.Context) error {
+	s.closed = true
+	return nil
+}
+
+type ServiceBusTestSuite struct {
+	suite.Suite
+	TestServiceBus *ServiceBus
+	senders        map[string]*fakeSender
+	created        int
+	createErr      error
+	mutex          sync.Mutex
+}
+
+func (suite *ServiceBusTestSuite) SetupTest() {
+	suite.senders = make(map[string]*fakeSender)
+	suite.created = 0
+	suite.createErr = nil
+	suite.TestServiceBus = &ServiceBus{
+		newSender: func(topic string) (sender, error) {
+			suite.mutex.Lock()
+			defer suite.mutex.Unlock()
+
+			if suite.createErr != nil {
+				return nil, suite.createErr
+			}
+
+			suite.created++
+			suite.senders[topic] = &fakeSender{}
+
+			return suite.senders[topic], nil
+		},
+		senders: make(map[string]sender),
+	}
+}
+
+func (suite *ServiceBusTestSuite) TestServiceBus_SendMessage_ReusesSender() {
+	for i := 0; i < 3; i++ {
+		suite.NoError(suite.TestServiceBus.SendMessage(context.Background(), "user.create", &azservicebus.Message{}))
+	}
+
+	suite.NoError(suite.TestServiceBus.SendMessage(context.Background(), "user.update", &azservicebus.Message{}))
+
+	suite.Equal(2, suite.created)
+	suite.Len(suite.senders["user.create"].sent, 3)
+	suite.Len(suite.senders["user.update"].sent, 1)
+}
+
+func (suite *ServiceBusTestSuite) TestServiceBus_SendMessage_Concurrent() {
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			suite.NoError(suite.TestServiceBus.SendMessage(context.Background(), "user.create", &azservicebus.Message{}))
+		}()
+	}
+
+	wg.Wait()
+
+	suite.Equal(1, suite.created)
+	suite.Len(suite.senders["user.create"].sent, 50)
+}
+
+func (suite *ServiceBusTestSuite) TestServiceBus_SendMessage_SenderFailed() {
+	suite.createErr = errors.New("topic not found")
+
+	err := suite.TestServiceBus.SendMessage(context.Background(), "user.create", &azservicebus.Message{})
+
+	suite.Error(err)
+
+	suite.createErr = nil
+
+	suite.NoError(suite.TestServiceBus.SendMessage(context.Background(), "user.create", &azservicebus.Message{}))
+}
+
+func (suite *ServiceBusTestSuite) TestServiceBus_Close() {
+	suite.NoError(suite.TestServiceBus.SendMessage(context.Background(), "user.create", &azservicebus.Message{}))
+
+	suite.TestServiceBus.Close()
+	suite.TestServiceBus.Close()
+
+	suite.True(suite.senders["user.create"].closed)
+
+	err := suite.TestServiceBus.SendMessage(context.Background(), "user.create", &azservicebus.Message{})
+
+	suite.ErrorIs(err, ErrClosed)
+}
+
+func TestUnit_ServiceBusTestSuite(t *testing.T) {
+	suite.Run(t, new(ServiceBusTestSuite))
+}
@@ -0,0 +1,181 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	SpecVersion = "1.0"
+
+	BinaryMode     = "binary"
+	StructuredMode = "structured"
+
+	JSONContentType       = "application/json"
+	StructuredContentType = "application/cloudevents+json"
+
+	// HeaderPrefix is prepended to the context attributes when they are sent as
+	// message headers or application properties in binary mode, as defined by
+	// the AMQP protocol binding.
+	HeaderPrefix = "cloudEvents:"
+
+	// KafkaHeaderPrefix replaces HeaderPrefix in the Kafka protocol binding.
+	KafkaHeaderPrefix = "ce_"
+
+	// NATSHeaderPrefix replaces HeaderPrefix in the NATS protocol binding.
+	NATSHeaderPrefix = "ce-"
+)
+
+// Event is a CloudEvents 1.0 event carrying JSON data.
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	DataSchema      string          `json:"dataschema,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+
+	// Extensions are additional context attributes. Names must be lower-case
+	// alphanumeric and must not clash with the attributes above.
+	Extensions map[string]string `json:"-"`
+}
+
+var contextAttributes = map[string]bool{
+	"specversion": true, "id": true, "source": true, "type": true, "subject": true,
+	"time": true, "datacontenttype": true, "dataschema": true, "data": true,
+}
+
+func NewJSONEvent(id, source, eventType, subject string, at time.Time, data interface{}) (Event, error) {
+	if id == "" || source == "" || eventType == "" {
+		return Event{}, errors.New("cloudevents: id, source and type are required")
+	}
+
+	js, err := json.Marshal(data)
+
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              id,
+		Source:          source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            at.UTC(),
+		DataContentType: JSONContentType,
+		Data:            js,
+	}, nil
+}
+
+// Headers returns the context attributes for binary mode. The data content type
+// is not included, it is carried by the content type of the message itself.
+func (event Event) Headers() map[string]interface{} {
+	return event.prefixedHeaders(HeaderPrefix)
+}
+
+// KafkaHeaders returns the context attributes for binary mode in the Kafka
+// protocol binding. Like Headers, the data content type is not included.
+func (event Event) KafkaHeaders() map[string]interface{} {
+	return event.prefixedHeaders(KafkaHeaderPrefix)
+}
+
+// NATSHeaders returns the context attributes for binary mode in the NATS
+// protocol binding. Like Headers, the data content type is not included.
+func (event Event) NATSHeaders() map[string]interface{} {
+	return event.prefixedHeaders(NATSHeaderPrefix)
+}
+
+func (event Event) prefixedHeaders(prefix string) map[string]interface{} {
+	headers := map[string]interface{}{
+		prefix + "specversion": event.SpecVersion,
+		prefix + "id":          event.ID,
+		prefix + "source":      event.Source,
+		prefix + "type":        event.Type,
+		prefix + "time":        event.Time.Format(time.RFC3339Nano),
+	}
+
+	if event.Subject != "" {
+		headers[prefix+"subject"] = event.Subject
+	}
+
+	if event.DataSchema != "" {
+		headers[prefix+"dataschema"] = event.DataSchema
+	}
+
+	for name, value := range event.Extensions {
+		headers[prefix+name] = value
+	}
+
+	return headers
+}
+
+// Structured returns the event with its data as a single JSON document.
+func (event Event) Structured() ([]byte, error) {
+	return json.Marshal(event)
+}
+
+func (event Event) MarshalJSON() ([]byte, error) {
+	type attributes Event
+
+	js, err := json.Marshal(attributes(event))
+
+	if err != nil || len(event.Extensions) == 0 {
+		return js, err
+	}
+
+	var document map[string]interface{}
+
+	if err = json.Unmarshal(js, &document); err != nil {
+		return nil, err
+	}
+
+	for name, value := range event.Extensions {
+		if contextAttributes[name] {
+			return nil, fmt.Errorf("cloudevents: extension %q clashes with a context attribute", name)
+		}
+
+		document[name] = value
+	}
+
+	return json.Marshal(document)
+}
+
+func (event *Event) UnmarshalJSON(data []byte) error {
+	type attributes Event
+
+	var decoded attributes
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	var document map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	for name, raw := range document {
+		var value string
+
+		if contextAttributes[name] || json.Unmarshal(raw, &value) != nil {
+			continue
+		}
+
+		if decoded.Extensions == nil {
+			decoded.Extensions = make(map[string]string)
+		}
+
+		decoded.Extensions[name] = value
+	}
+
+	*event = Event(decoded)
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type EventTestSuite struct {
+	suite.Suite
+	at time.Time
+}
+
+func (suite *EventTestSuite) SetupSuite() {
+	suite.at = time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *EventTestSuite) TestEvent_NewJSONEvent() {
+	event, err := NewJSONEvent("id", "/test", "test.created", "subject", suite.at, map[string]string{"name": "test"})
+
+	suite.NoError(err)
+	suite.Equal(SpecVersion, event.SpecVersion)
+	suite.Equal(JSONContentType, event.DataContentType)
+	suite.JSONEq(`{"name": "test"}`, string(event.Data))
+}
+
+func (suite *EventTestSuite) TestEvent_NewJSONEvent_MissingAttributes() {
+	_, err := NewJSONEvent("", "/test", "test.created", "", suite.at, nil)
+
+	suite.Error(err)
+}
+
+func (suite *EventTestSuite) TestEvent_Headers() {
+	event, _ := NewJSONEvent("id", "/test", "test.created", "subject", suite.at, map[string]string{"name": "test"})
+	event.DataSchema = "https://example.com/schema"
+
+	headers := event.Headers()
+
+	suite.Equal("1.0", headers["cloudEvents:specversion"])
+	suite.Equal("id", headers["cloudEvents:id"])
+	suite.Equal("/test", headers["cloudEvents:source"])
+	suite.Equal("test.created", headers["cloudEvents:type"])
+	suite.Equal("subject", headers["cloudEvents:subject"])
+	suite.Equal("2022-01-01T12:00:00Z", headers["cloudEvents:time"])
+	suite.Equal("https://example.com/schema", headers["cloudEvents:dataschema"])
+	suite.NotContains(headers, "cloudEvents:datacontenttype")
+}
+
+func (suite *EventTestSuite) TestEvent_Headers_OmitsEmptyOptionalAttributes() {
+	event, _ := NewJSONEvent("id", "/test", "test.created", "", suite.at, nil)
+
+	headers := event.Headers()
+
+	suite.NotContains(headers, "cloudEvents:subject")
+	suite.NotContains(headers, "cloudEvents:dataschema")
+}
+
+func (suite *EventTestSuite) TestEvent_KafkaHeaders() {
+	event, _ := NewJSONEvent("id", "/test", "test.created", "subject", suite.at, nil)
+
+	headers := event.KafkaHeaders()
+
+	suite.Equal("1.0", headers["ce_specversion"])
+	suite.Equal("id", headers["ce_id"])
+	suite.Equal("subject", headers["ce_subject"])
+	suite.NotContains(headers, "cloudEvents:id")
+}
+
+func (suite *EventTestSuite) TestEvent_NATSHeaders() {
+	event, _ := NewJSONEvent("id", "/test", "test.created", "subject", suite.at, nil)
+
+	headers := event.NATSHeaders()
+
+	suite.Equal("id", headers["ce-id"])
+	suite.Equal("test.created", headers["ce-type"])
+	suite.NotContains(headers, "ce_id")
+}
+
+func (suite *EventTestSuite) TestEvent_Structured() {
+	event, _ := NewJSONEvent("id", "/test", "test.created", "subject", suite.at, map[string]string{"name": "test"})
+
+	js, err := event.Structured()
+
+	suite.NoError(err)
+	suite.JSONEq(`{
+		"specversion": "1.0",
+		"id": "id",
+		"source": "/test",
+		"type": "test.created",
+		"subject": "subject",
+		"time": "2022-01-01T12:00:00Z",
+		"datacontenttype": "application/json",
+		"data": {"name": "test"}
+	}`, string(js))
+
+	var decoded Event
+
+	suite.NoError(json.Unmarshal(js, &decoded))
+	suite.Equal(event, decoded)
+}
+
+func (suite *EventTestSuite) TestEvent_Extensions() {
+	event, _ := NewJSONEvent("id", "/test", "test.created", "", suite.at, map[string]string{"name": "test"})
+	event.Extensions = map[string]string{"schemaversion": "2"}
+
+	suite.Equal("2", event.Headers()["cloudEvents:schemaversion"])
+
+	js, err := event.Structured()
+
+	suite.NoError(err)
+	suite.Contains(string(js), `"schemaversion":"2"`)
+
+	var decoded Event
+
+	suite.NoError(json.Unmarshal(js, &decoded))
+	suite.Equal(event, decoded)
+}
+
+func (suite *EventTestSuite) TestEvent_Extensions_Clash() {
+	event, _ := NewJSONEvent("id", "/test", "test.created", "", suite.at, nil)
+	event.Extensions = map[string]string{"type": "other"}
+
+	_, err := event.Structured()
+
+	suite.Error(err)
+}
+
+func TestUnit_EventTestSuite(t *testing.T) {
+	suite.Run(t, new(EventTestSuite))
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+	"user-service/internal/core/domain"
+)
+
+type DeadLetterResponse struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Reason      string          `json:"reason"`
+	Description string          `json:"description,omitempty"`
+	Attempts    int             `json:"attempts"`
+	FailedAt    *time.Time      `json:"failed_at,omitempty"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body"`
+}
+
+type DeadLetterPurgeResponse struct {
+	Purged int `json:"purged"`
+}
+
+// CreateDeadLetterResponse embeds JSON bodies as they are, other bodies as a string.
+func CreateDeadLetterResponse(deadLetter domain.DeadLetter) DeadLetterResponse {
+	body := json.RawMessage(deadLetter.Body)
+
+	if !json.Valid(body) {
+		body, _ = json.Marshal(string(deadLetter.Body))
+	}
+
+	return DeadLetterResponse{
+		ID:          deadLetter.ID,
+		Type:        deadLetter.Type,
+		Reason:      deadLetter.Reason,
+		Description: deadLetter.Description,
+		Attempts:    deadLetter.Attempts,
+		FailedAt:    deadLetter.FailedAt,
+		ContentType: deadLetter.ContentType,
+		Body:        body,
+	}
+}
+
+func CreateDeadLetterListResponse(deadLetters []domain.DeadLetter) []DeadLetterResponse {
+	response := make([]DeadLetterResponse, 0, len(deadLetters))
+
+	for _, deadLetter := range deadLetters {
+		response = append(response, CreateDeadLetterResponse(deadLetter))
+	}
+
+	return response
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "user-service/pkg/events"
+
+type EventSchemaResponse struct {
+	Type      string        `json:"type"`
+	Version   int           `json:"version"`
+	Current   bool          `json:"current"`
+	SchemaURL string        `json:"schema_url"`
+	Schema    events.Schema `json:"schema"`
+}
+
+func CreateEventSchemaResponse(definition events.Definition, baseURL string) EventSchemaResponse {
+	current, _ := events.Current(definition.Type)
+
+	return EventSchemaResponse{
+		Type:      definition.Type,
+		Version:   definition.Version,
+		Current:   current.Version == definition.Version,
+		SchemaURL: baseURL + "/" + definition.Path(),
+		Schema:    definition.Schema(baseURL),
+	}
+}
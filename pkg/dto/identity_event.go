@@ -0,0 +1,12 @@
+package dto
+
+type IdentityUserRegistered struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	LastName string `json:"last_name"`
+	Email    string `json:"email"`
+}
+
+type IdentityUserDeleted struct {
+	ID string `json:"id"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"user-service/internal/core/domain"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+var ErrUnsupportedPatch = errors.New("unsupported patch content type")
+
+// PatchUser applies a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) document,
+// depending on the content type, to the representation of the user.
+func PatchUser(user domain.User, contentType string, patch []byte) (BodyCreateUser, error) {
+	document, err := json.Marshal(BodyCreateUser{
+		ID:       user.ID,
+		Name:     user.Name,
+		LastName: user.LastName,
+		Email:    user.Email,
+	})
+
+	if err != nil {
+		return BodyCreateUser{}, err
+	}
+
+	switch contentType {
+	case MergePatchContentType:
+		document, err = jsonpatch.MergePatch(document, patch)
+	case JSONPatchContentType:
+		var operations jsonpatch.Patch
+		operations, err = jsonpatch.DecodePatch(patch)
+
+		if err == nil {
+			document, err = operations.Apply(document)
+		}
+	default:
+		return BodyCreateUser{}, ErrUnsupportedPatch
+	}
+
+	if err != nil {
+		return BodyCreateUser{}, domain.NewValidationError("patch", err.Error())
+	}
+
+	var patched BodyCreateUser
+
+	if err = json.Unmarshal(document, &patched); err != nil {
+		return BodyCreateUser{}, domain.NewValidationError("patch", err.Error())
+	}
+
+	if patched.ID != user.ID {
+		return BodyCreateUser{}, domain.NewValidationError("id", "id can not be changed")
+	}
+
+	return patched, nil
+}
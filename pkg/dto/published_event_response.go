@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+	"user-service/internal/core/domain"
+)
+
+type PublishedEventResponse struct {
+	Topic   string          `json:"topic"`
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Subject string          `json:"subject"`
+	Time    time.Time       `json:"time"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func CreatePublishedEventListResponse(events []domain.PublishedEvent) []PublishedEventResponse {
+	response := make([]PublishedEventResponse, 0, len(events))
+
+	for _, event := range events {
+		response = append(response, PublishedEventResponse{
+			Topic:   event.Topic,
+			ID:      event.ID,
+			Type:    event.Type,
+			Subject: event.Subject,
+			Time:    event.Time,
+			Data:    event.Data,
+		})
+	}
+
+	return response
+}
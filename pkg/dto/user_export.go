@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+	"user-service/internal/core/domain"
+)
+
+type UserExportResponse struct {
+	ExportedAt time.Time          `json:"exported_at"`
+	User       exportUserResponse `json:"user"`
+}
+
+// exportUserResponse holds every stored field of the user.
+type exportUserResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	LastName  string     `json:"last_name"`
+	Email     string     `json:"email"`
+	ErasedAt  *time.Time `json:"erased_at,omitempty"`
+	ErasedBy  string     `json:"erased_by,omitempty"`
+	Version   int64      `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+func CreateUserExportResponse(export domain.UserExport) UserExportResponse {
+	var deletedAt *time.Time
+
+	if export.User.DeletedAt.Valid {
+		deletedAt = &export.User.DeletedAt.Time
+	}
+
+	return UserExportResponse{
+		ExportedAt: export.ExportedAt,
+		User: exportUserResponse{
+			ID:        export.User.ID,
+			Name:      export.User.Name,
+			LastName:  export.User.LastName,
+			Email:     export.User.Email,
+			ErasedAt:  export.User.ErasedAt,
+			ErasedBy:  export.User.ErasedBy,
+			Version:   export.User.Version,
+			CreatedAt: export.User.CreatedAt,
+			DeletedAt: deletedAt,
+		},
+	}
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "user-service/internal/core/domain"
+
+type UserSearchResponse struct {
+	Users UserListResponse `json:"users"`
+	Page  int              `json:"page"`
+	Size  int              `json:"size"`
+	Total int64            `json:"total"`
+}
+
+func CreateUserSearchResponse(result domain.UserSearchResult, page domain.SearchPage) UserSearchResponse {
+	return UserSearchResponse{
+		Users: CreateUserListResponse(result.Users),
+		Page:  page.Number,
+		Size:  page.Size,
+		Total: result.Total,
+	}
+}
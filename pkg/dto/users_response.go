@@ -26,3 +26,17 @@ func CreateUserListResponse(users []domain.User) UserListResponse {
 	}
 	return response
 }
+
+type UserPageResponse struct {
+	Users      UserListResponse `json:"users"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+	Total      int64            `json:"total"`
+}
+
+func CreateUserPageResponse(page domain.UserPage) UserPageResponse {
+	return UserPageResponse{
+		Users:      CreateUserListResponse(page.Users),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
+}
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schema is a JSON Schema document.
+type Schema map[string]interface{}
+
+// GenerateSchema builds a JSON Schema for the type of v from its json tags.
+// Fields without omitempty are required, pointers are nullable and a
+// description tag is copied into the schema of the field.
+func GenerateSchema(id, title string, v interface{}) Schema {
+	schema := typeSchema(reflect.TypeOf(v))
+	schema["$schema"] = jsonSchemaDraft
+	schema["$id"] = id
+	schema["title"] = title
+
+	return schema
+}
+
+func typeSchema(t reflect.Type) Schema {
+	if t == timeType {
+		return Schema{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := typeSchema(t.Elem())
+		schema["type"] = []interface{}{schema["type"], "null"}
+
+		return schema
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		return Schema{}
+	}
+}
+
+func structSchema(t reflect.Type) Schema {
+	properties := Schema{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, options := field.Name, ""
+
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+
+			name, options = splitTag(tag, field.Name)
+		}
+
+		schema := typeSchema(field.Type)
+
+		if description := field.Tag.Get("description"); description != "" {
+			schema["description"] = description
+		}
+
+		properties[name] = schema
+
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	return Schema{"type": "object", "properties": properties, "required": required}
+}
+
+func splitTag(tag, fallback string) (string, string) {
+	name, options := tag, ""
+
+	if i := strings.Index(tag, ","); i >= 0 {
+		name, options = tag[:i], tag[i+1:]
+	}
+
+	if name == "" {
+		name = fallback
+	}
+
+	return name, options
+}
@@ -0,0 +1,116 @@
+package events
+
+import (
+	"fmt"
+	"time"
+	"user-service/internal/core/domain"
+)
+
+const (
+	UserCreated = "bikepack.user.created"
+	UserUpdated = "bikepack.user.updated"
+	UserDeleted = "bikepack.user.deleted"
+	UserErased  = "bikepack.user.erased"
+)
+
+// UserV1 is version 1 of the payload of the created, updated and deleted events.
+// Published payloads are a contract with consumers: fields may only be added
+// here as optional, anything else needs a new version.
+type UserV1 struct {
+	ID       string `json:"id" description:"Id of the user"`
+	Name     string `json:"name"`
+	LastName string `json:"last_name"`
+	Email    string `json:"email"`
+	Version  int64  `json:"version" description:"Incremented on every change of the user"`
+}
+
+// UserErasedV1 is version 1 of the payload of the erased event.
+type UserErasedV1 struct {
+	ID       string    `json:"id" description:"Id of the user"`
+	Name     string    `json:"name" description:"Pseudonym replacing the name"`
+	LastName string    `json:"last_name" description:"Pseudonym replacing the last name"`
+	Email    string    `json:"email" description:"Pseudonym replacing the email"`
+	Version  int64     `json:"version" description:"Incremented on every change of the user"`
+	ErasedAt time.Time `json:"erased_at"`
+	ErasedBy string    `json:"erased_by" description:"Id of the user that requested the erasure"`
+}
+
+func NewUserV1(user domain.User) UserV1 {
+	return UserV1{
+		ID:       user.ID,
+		Name:     user.Name,
+		LastName: user.LastName,
+		Email:    user.Email,
+		Version:  user.Version,
+	}
+}
+
+func NewUserErasedV1(user domain.User) UserErasedV1 {
+	erased := UserErasedV1{
+		ID:       user.ID,
+		Name:     user.Name,
+		LastName: user.LastName,
+		Email:    user.Email,
+		Version:  user.Version,
+		ErasedBy: user.ErasedBy,
+	}
+
+	if user.ErasedAt != nil {
+		erased.ErasedAt = *user.ErasedAt
+	}
+
+	return erased
+}
+
+// Definition is one version of the payload of an event type.
+type Definition struct {
+	Type    string
+	Version int
+	payload interface{}
+	create  func(user domain.User) interface{}
+}
+
+// Definitions lists every published payload version, oldest first. Old
+// versions stay listed so their schemas remain available during rollouts.
+var Definitions = []Definition{
+	{Type: UserCreated, Version: 1, payload: UserV1{}, create: func(user domain.User) interface{} { return NewUserV1(user) }},
+	{Type: UserUpdated, Version: 1, payload: UserV1{}, create: func(user domain.User) interface{} { return NewUserV1(user) }},
+	{Type: UserDeleted, Version: 1, payload: UserV1{}, create: func(user domain.User) interface{} { return NewUserV1(user) }},
+	{Type: UserErased, Version: 1, payload: UserErasedV1{}, create: func(user domain.User) interface{} { return NewUserErasedV1(user) }},
+}
+
+// Current returns the newest version of the event type, which is the one published.
+func Current(eventType string) (Definition, bool) {
+	var current Definition
+
+	for _, definition := range Definitions {
+		if definition.Type == eventType && definition.Version > current.Version {
+			current = definition
+		}
+	}
+
+	return current, current.Version > 0
+}
+
+func Find(eventType string, version int) (Definition, bool) {
+	for _, definition := range Definitions {
+		if definition.Type == eventType && definition.Version == version {
+			return definition, true
+		}
+	}
+
+	return Definition{}, false
+}
+
+func (definition Definition) Payload(user domain.User) interface{} {
+	return definition.create(user)
+}
+
+// Path is where the schema is served, relative to the schema base url.
+func (definition Definition) Path() string {
+	return fmt.Sprintf("%s/%d", definition.Type, definition.Version)
+}
+
+func (definition Definition) Schema(baseURL string) Schema {
+	return GenerateSchema(fmt.Sprintf("%s/%s", baseURL, definition.Path()), definition.Type, definition.payload)
+}
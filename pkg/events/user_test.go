@@ -0,0 +1,109 @@
+package events
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+	"user-service/internal/core/domain"
+)
+
+type EventsTestSuite struct {
+	suite.Suite
+	user domain.User
+}
+
+func (suite *EventsTestSuite) SetupSuite() {
+	suite.user = domain.User{
+		ID:       "test-id",
+		Name:     "test-name",
+		LastName: "test-lastname",
+		Email:    "test@test.com",
+		Version:  3,
+	}
+}
+
+func (suite *EventsTestSuite) TestEvents_Current() {
+	for _, eventType := range []string{UserCreated, UserUpdated, UserDeleted, UserErased} {
+		definition, ok := Current(eventType)
+
+		suite.True(ok, eventType)
+		suite.Equal(1, definition.Version)
+	}
+
+	_, ok := Current("unknown")
+
+	suite.False(ok)
+}
+
+func (suite *EventsTestSuite) TestEvents_Payload() {
+	definition, _ := Current(UserUpdated)
+
+	js, err := json.Marshal(definition.Payload(suite.user))
+
+	suite.NoError(err)
+	suite.JSONEq(`{"id": "test-id", "name": "test-name", "last_name": "test-lastname", "email": "test@test.com", "version": 3}`, string(js))
+}
+
+func (suite *EventsTestSuite) TestEvents_Payload_Erased() {
+	user := suite.user
+	user.Erase("admin-id", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	definition, _ := Current(UserErased)
+	payload := definition.Payload(user).(UserErasedV1)
+
+	suite.Equal("admin-id", payload.ErasedBy)
+	suite.Equal(*user.ErasedAt, payload.ErasedAt)
+	suite.Equal(user.Email, payload.Email)
+}
+
+func (suite *EventsTestSuite) TestEvents_Schema() {
+	definition, _ := Find(UserCreated, 1)
+
+	js, err := json.Marshal(definition.Schema("https://example.com/schemas"))
+
+	suite.NoError(err)
+	suite.JSONEq(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/schemas/bikepack.user.created/1",
+		"title": "bikepack.user.created",
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "description": "Id of the user"},
+			"name": {"type": "string"},
+			"last_name": {"type": "string"},
+			"email": {"type": "string"},
+			"version": {"type": "integer", "description": "Incremented on every change of the user"}
+		},
+		"required": ["id", "name", "last_name", "email", "version"]
+	}`, string(js))
+}
+
+func (suite *EventsTestSuite) TestEvents_GenerateSchema() {
+	type nested struct {
+		Tags []string `json:"tags,omitempty"`
+	}
+
+	type payload struct {
+		At       time.Time  `json:"at"`
+		Optional *time.Time `json:"optional"`
+		Count    int        `json:"count,omitempty"`
+		Nested   nested     `json:"nested"`
+		Ignored  string     `json:"-"`
+		hidden   string
+	}
+
+	schema := GenerateSchema("id", "title", payload{})
+	properties := schema["properties"].(Schema)
+
+	suite.Equal(Schema{"type": "string", "format": "date-time"}, properties["at"])
+	suite.Equal([]interface{}{"string", "null"}, properties["optional"].(Schema)["type"])
+	suite.Equal([]string{"at", "optional", "nested"}, schema["required"])
+	suite.Equal(Schema{"type": "array", "items": Schema{"type": "string"}}, properties["nested"].(Schema)["properties"].(Schema)["tags"])
+	suite.NotContains(properties, "Ignored")
+	suite.NotContains(properties, "hidden")
+}
+
+func TestUnit_EventsTestSuite(t *testing.T) {
+	suite.Run(t, new(EventsTestSuite))
+}
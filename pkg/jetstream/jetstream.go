@@ -0,0 +1,103 @@
+package jetstream
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/config"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	defaultStream          = "USERS"
+	defaultDuplicateWindow = 2 * time.Minute
+	defaultPublishTimeout  = 5 * time.Second
+)
+
+// subjects are stored in the stream, the user events are published to user.<x>.
+var subjects = []string{"user.>"}
+
+// JetStream publishes messages to a NATS JetStream stream. The stream is
+// created when it does not exist yet. Messages carrying a Nats-Msg-Id header
+// are deduplicated by the server within the duplicate window of the stream.
+// It is safe for concurrent use.
+type JetStream struct {
+	conn           *nats.Conn
+	js             nats.JetStreamContext
+	publishTimeout time.Duration
+}
+
+func NewJetStream(cfg *config.Config) (*JetStream, error) {
+	stream := cfg.NATS.Stream
+
+	if stream == "" {
+		stream = defaultStream
+	}
+
+	duplicateWindow := cfg.NATS.DuplicateWindow
+
+	if duplicateWindow <= 0 {
+		duplicateWindow = defaultDuplicateWindow
+	}
+
+	publishTimeout := cfg.NATS.PublishTimeout
+
+	if publishTimeout <= 0 {
+		publishTimeout = defaultPublishTimeout
+	}
+
+	conn, err := nats.Connect(cfg.NATS.URL, nats.Name(cfg.Server.Service), nats.MaxReconnects(-1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := conn.JetStream()
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	_, err = js.StreamInfo(stream)
+
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:       stream,
+			Subjects:   subjects,
+			Storage:    nats.FileStorage,
+			Duplicates: duplicateWindow,
+		})
+	}
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &JetStream{conn: conn, js: js, publishTimeout: publishTimeout}, nil
+}
+
+// Publish stores the message in the stream and waits for the acknowledgement
+// of the server, or until the publish timeout passed. A message that was
+// already stored with the same message id is acknowledged as a duplicate and
+// not stored again.
+func (j *JetStream) Publish(ctx context.Context, msg *nats.Msg) error {
+	ctx, cancel := context.WithTimeout(ctx, j.publishTimeout)
+	defer cancel()
+
+	_, err := j.js.PublishMsg(msg, nats.Context(ctx))
+
+	return err
+}
+
+// Context returns the JetStream context, for consumers.
+func (j *JetStream) Context() nats.JetStreamContext {
+	return j.js
+}
+
+// Close flushes pending messages and closes the connection.
+func (j *JetStream) Close() {
+	_ = j.conn.Drain()
+}
@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+	"user-service/config"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const defaultProduceTimeout = 10 * time.Second
+
+var ErrClosed = errors.New("kafka: closed")
+
+// Kafka produces records to the brokers. Writes are idempotent and require the
+// acknowledgement of all in-sync replicas, so retried produce requests neither
+// duplicate nor reorder the records of a partition. Records are partitioned by
+// their key. It is safe for concurrent use.
+type Kafka struct {
+	client         *kgo.Client
+	produceTimeout time.Duration
+	mutex          sync.RWMutex
+	closed         bool
+}
+
+func NewKafka(cfg *config.Config) (*Kafka, error) {
+	produceTimeout := cfg.Kafka.ProduceTimeout
+
+	if produceTimeout <= 0 {
+		produceTimeout = defaultProduceTimeout
+	}
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(cfg.Kafka.Brokers...),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.RecordPartitioner(kgo.StickyKeyPartitioner(nil)),
+		kgo.RecordDeliveryTimeout(produceTimeout),
+	}
+
+	if cfg.Kafka.ClientID != "" {
+		opts = append(opts, kgo.ClientID(cfg.Kafka.ClientID))
+	}
+
+	client, err := kgo.NewClient(opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Kafka{client: client, produceTimeout: produceTimeout}, nil
+}
+
+// Produce writes the record and waits until the brokers acknowledged it, or
+// the produce timeout passed.
+func (k *Kafka) Produce(ctx context.Context, record *kgo.Record) error {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if k.closed {
+		return ErrClosed
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, k.produceTimeout)
+	defer cancel()
+
+	return k.client.ProduceSync(ctx, record).FirstErr()
+}
+
+// Close flushes buffered records and closes the connections to the brokers.
+func (k *Kafka) Close() {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if k.closed {
+		return
+	}
+
+	k.closed = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), k.produceTimeout)
+	defer cancel()
+
+	_ = k.client.Flush(ctx)
+	k.client.Close()
+}
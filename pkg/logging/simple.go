@@ -3,15 +3,26 @@ package logging
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 	"user-service/config"
 )
 
+// SimpleLogger writes one line per entry with the key/value pairs appended as
+// key=value, quoting values that contain whitespace or quotes. Fatal exits the
+// process with status 1 after writing the entry.
 type SimpleLogger struct {
 	Config *config.Config
+	// Out receives the entries, os.Stdout when nil.
+	Out io.Writer
+	// exit ends the process after a fatal entry, os.Exit when nil.
+	exit func(code int)
 }
 
 func NewSimpleLogger(cfg *config.Config) (*SimpleLogger, error) {
-	return &SimpleLogger{Config: cfg}, nil
+	return &SimpleLogger{Config: cfg, Out: os.Stdout}, nil
 }
 
 func (l *SimpleLogger) Close() error {
@@ -23,21 +34,63 @@ func (l *SimpleLogger) Panic(ctx context.Context, args ...interface{}) {
 }
 
 func (l *SimpleLogger) Fatal(ctx context.Context, args ...interface{}) {
-	fmt.Println("FATAL: ", args)
+	l.write("FATAL", fmt.Sprint(args...), nil)
+
+	exit := l.exit
+
+	if exit == nil {
+		exit = os.Exit
+	}
+
+	exit(1)
 }
 
 func (l *SimpleLogger) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	fmt.Printf("INFO: "+msg+"\n", keysAndValues)
+	l.write("INFO", msg, keysAndValues)
 }
 
 func (l *SimpleLogger) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	fmt.Printf("DEBUG: "+msg+"\n", keysAndValues)
+	l.write("DEBUG", msg, keysAndValues)
 }
 
 func (l *SimpleLogger) Warning(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	fmt.Printf("WARNING: "+msg+"\n", keysAndValues)
+	l.write("WARNING", msg, keysAndValues)
 }
 
 func (l *SimpleLogger) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	fmt.Printf("ERROR: "+msg+"\n", keysAndValues)
+	l.write("ERROR", msg, keysAndValues)
+}
+
+func (l *SimpleLogger) write(level, msg string, keysAndValues []interface{}) {
+	var line strings.Builder
+
+	line.WriteString(level + ": " + msg)
+
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, value := "!BADKEY", keysAndValues[i]
+
+		if i+1 < len(keysAndValues) {
+			key, value = fmt.Sprint(keysAndValues[i]), keysAndValues[i+1]
+		}
+
+		line.WriteString(" " + key + "=" + formatValue(value))
+	}
+
+	out := l.Out
+
+	if out == nil {
+		out = os.Stdout
+	}
+
+	fmt.Fprintln(out, line.String())
+}
+
+func formatValue(value interface{}) string {
+	text := fmt.Sprint(value)
+
+	if text == "" || strings.ContainsAny(text, " \t\n\"=") {
+		return strconv.Quote(text)
+	}
+
+	return text
 }
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type SimpleLoggerTestSuite struct {
+	suite.Suite
+	Output *bytes.Buffer
+	Logger *SimpleLogger
+}
+
+func (suite *SimpleLoggerTestSuite) SetupTest() {
+	suite.Output = &bytes.Buffer{}
+	suite.Logger = &SimpleLogger{Out: suite.Output}
+}
+
+func (suite *SimpleLoggerTestSuite) TestSimpleLogger_KeysAndValues() {
+	suite.Logger.Warning(context.Background(), "relaying outbox message failed",
+		"id", 7, "type", "user.create", "error", errors.New("broker unavailable"))
+
+	suite.Equal("WARNING: relaying outbox message failed id=7 type=user.create error=\"broker unavailable\"\n", suite.Output.String())
+}
+
+func (suite *SimpleLoggerTestSuite) TestSimpleLogger_MessageOnly() {
+	suite.Logger.Info(context.Background(), "100% done")
+
+	suite.Equal("INFO: 100% done\n", suite.Output.String())
+}
+
+func (suite *SimpleLoggerTestSuite) TestSimpleLogger_MissingValue() {
+	suite.Logger.Error(context.Background(), "failed", "id", "test-id", "orphan")
+
+	suite.Equal("ERROR: failed id=test-id !BADKEY=orphan\n", suite.Output.String())
+}
+
+func (suite *SimpleLoggerTestSuite) TestSimpleLogger_Fatal() {
+	exitCode := -1
+	suite.Logger.exit = func(code int) { exitCode = code }
+
+	suite.Logger.Fatal(context.Background(), errors.New("listen failed"))
+
+	suite.Equal("FATAL: listen failed\n", suite.Output.String())
+	suite.Equal(1, exitCode)
+}
+
+func TestUnit_SimpleLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(SimpleLoggerTestSuite))
+}
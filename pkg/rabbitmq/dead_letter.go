@@ -0,0 +1,196 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	deadLetterSuffix    = ".dead-letter"
+	headerDeliveryCount = "x-delivery-count"
+)
+
+var ErrDeadLetterNotFound = errors.New("rabbitmq: dead letter not found")
+
+// DeadLetterName is the name of the dead letter exchange and queue of queue.
+func DeadLetterName(queue string) string {
+	return queue + deadLetterSuffix
+}
+
+// DeclareQueue declares a durable quorum queue with its dead letter exchange and
+// queue. The broker dead-letters messages that are rejected without requeueing
+// or that were delivered more than maxDeliveries times.
+func DeclareQueue(channel *amqp.Channel, queue string, maxDeliveries int) error {
+	deadLetter := DeadLetterName(queue)
+
+	err := channel.ExchangeDeclare(deadLetter, "fanout", true, false, false, false, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = channel.QueueDeclare(deadLetter, true, false, false, false, amqp.Table{
+		"x-queue-type": "quorum",
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = channel.QueueBind(deadLetter, "", deadLetter, false, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = channel.QueueDeclare(queue, true, false, false, false, amqp.Table{
+		"x-queue-type":           "quorum",
+		"x-delivery-limit":       maxDeliveries,
+		"x-dead-letter-exchange": deadLetter,
+	})
+
+	return err
+}
+
+// Attempts returns how often the delivery has been delivered, including this
+// delivery. Only quorum queues count deliveries.
+func Attempts(delivery amqp.Delivery) int {
+	switch count := delivery.Headers[headerDeliveryCount].(type) {
+	case int32:
+		return int(count) + 1
+	case int64:
+		return int(count) + 1
+	default:
+		return 1
+	}
+}
+
+// DeadLetter publishes a copy of the delivery with the extra headers to the dead
+// letter exchange of queue. The caller acknowledges the delivery afterwards.
+func (r *RabbitMQ) DeadLetter(ctx context.Context, queue string, delivery amqp.Delivery, headers amqp.Table) error {
+	table := amqp.Table{}
+
+	for key, value := range delivery.Headers {
+		table[key] = value
+	}
+
+	for key, value := range headers {
+		table[key] = value
+	}
+
+	messageID := delivery.MessageId
+
+	if messageID == "" {
+		messageID = uuid.New().String()
+	}
+
+	return r.Publish(ctx, DeadLetterName(queue), delivery.RoutingKey, amqp.Publishing{
+		Headers:         table,
+		ContentType:     delivery.ContentType,
+		ContentEncoding: delivery.ContentEncoding,
+		DeliveryMode:    amqp.Persistent,
+		CorrelationId:   delivery.CorrelationId,
+		MessageId:       messageID,
+		Timestamp:       delivery.Timestamp,
+		Type:            delivery.Type,
+		Body:            delivery.Body,
+	})
+}
+
+// PeekDeadLetters returns up to limit messages of the dead letter queue of queue
+// without removing them.
+func (r *RabbitMQ) PeekDeadLetters(queue string, limit int) ([]amqp.Delivery, error) {
+	channel, err := r.OpenChannel()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Closing the channel requeues every message that was not acknowledged.
+	defer channel.Close()
+
+	deliveries := make([]amqp.Delivery, 0, limit)
+
+	for len(deliveries) < limit {
+		delivery, ok, err := channel.Get(DeadLetterName(queue), false)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			break
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// FindDeadLetter returns the message with messageID from the dead letter queue
+// of queue without removing it.
+func (r *RabbitMQ) FindDeadLetter(queue, messageID string) (amqp.Delivery, error) {
+	var found amqp.Delivery
+
+	err := r.scanDeadLetters(queue, messageID, func(delivery amqp.Delivery) error {
+		found = delivery
+		return nil
+	}, false)
+
+	return found, err
+}
+
+// TakeDeadLetter calls fn with the message with messageID from the dead letter
+// queue of queue, and removes it when fn succeeds.
+func (r *RabbitMQ) TakeDeadLetter(queue, messageID string, fn func(delivery amqp.Delivery) error) error {
+	return r.scanDeadLetters(queue, messageID, fn, true)
+}
+
+func (r *RabbitMQ) scanDeadLetters(queue, messageID string, fn func(delivery amqp.Delivery) error, remove bool) error {
+	channel, err := r.OpenChannel()
+
+	if err != nil {
+		return err
+	}
+
+	defer channel.Close()
+
+	for {
+		delivery, ok, err := channel.Get(DeadLetterName(queue), false)
+
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return ErrDeadLetterNotFound
+		}
+
+		if delivery.MessageId != messageID {
+			continue
+		}
+
+		if err = fn(delivery); err != nil || !remove {
+			return err
+		}
+
+		return delivery.Ack(false)
+	}
+}
+
+// PurgeDeadLetters removes every message from the dead letter queue of queue.
+func (r *RabbitMQ) PurgeDeadLetters(queue string) (int, error) {
+	channel, err := r.OpenChannel()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer channel.Close()
+
+	return channel.QueuePurge(DeadLetterName(queue), false)
+}
@@ -0,0 +1,25 @@
+package rabbitmq
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type AttemptsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *AttemptsTestSuite) TestAttempts_FirstDelivery() {
+	suite.Equal(1, Attempts(amqp.Delivery{}))
+}
+
+func (suite *AttemptsTestSuite) TestAttempts_Redelivered() {
+	suite.Equal(3, Attempts(amqp.Delivery{Headers: amqp.Table{"x-delivery-count": int64(2)}}))
+	suite.Equal(2, Attempts(amqp.Delivery{Headers: amqp.Table{"x-delivery-count": int32(1)}}))
+}
+
+func TestUnit_AttemptsTestSuite(t *testing.T) {
+	suite.Run(t, new(AttemptsTestSuite))
+}
@@ -0,0 +1,156 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var (
+	ErrNacked         = errors.New("rabbitmq: message was nacked by the broker")
+	ErrConfirmTimeout = errors.New("rabbitmq: timed out waiting for the publisher confirm")
+	ErrChannelClosed  = errors.New("rabbitmq: channel closed while waiting for the publisher confirm")
+)
+
+// ReturnedError is returned when the broker could not route a mandatory message to any queue.
+type ReturnedError struct {
+	Exchange   string
+	RoutingKey string
+	ReplyCode  uint16
+	ReplyText  string
+}
+
+func (err *ReturnedError) Error() string {
+	return fmt.Sprintf("rabbitmq: message to %s with routing key %s was returned: %d %s",
+		err.Exchange, err.RoutingKey, err.ReplyCode, err.ReplyText)
+}
+
+// Publish sends a message and waits until the broker confirmed it. Unless
+// mandatory routing is disabled, a message that reaches no queue is an error.
+// Publishes are serialized so every confirm and return can be matched to the
+// message waiting for it. While reconnecting it fails with ErrNotConnected.
+func (r *RabbitMQ) Publish(ctx context.Context, exchange, key string, msg amqp.Publishing) error {
+	r.publishMutex.Lock()
+	defer r.publishMutex.Unlock()
+
+	r.mutex.RLock()
+	channel, confirms, returns := r.channel, r.confirms, r.returns
+	r.mutex.RUnlock()
+
+	if channel == nil {
+		return ErrNotConnected
+	}
+
+	drain(confirms, returns)
+
+	tag := channel.GetNextPublishSeqNo()
+
+	err := channel.Publish(exchange, key, r.mandatory, false, msg)
+
+	if err != nil {
+		return err
+	}
+
+	return waitForConfirm(ctx, tag, msg.MessageId, confirms, returns, r.confirmTimeout)
+}
+
+// waitForConfirm waits for the confirm of the delivery tag. The broker sends a
+// return before the confirm of the same message, so a return that is pending
+// once the ack arrived belongs to this message.
+func waitForConfirm(ctx context.Context, tag uint64, messageID string, confirms <-chan amqp.Confirmation, returns <-chan amqp.Return, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var returned *ReturnedError
+
+	for {
+		select {
+		case ret, ok := <-returns:
+			if !ok {
+				return ErrChannelClosed
+			}
+
+			if isReturnOf(ret, messageID) {
+				returned = newReturnedError(ret)
+			}
+		case confirm, ok := <-confirms:
+			if !ok {
+				return ErrChannelClosed
+			}
+
+			// Confirms of earlier publishes that timed out.
+			if confirm.DeliveryTag < tag {
+				continue
+			}
+
+			if !confirm.Ack {
+				return ErrNacked
+			}
+
+			if returned == nil {
+				returned = pendingReturn(returns, messageID)
+			}
+
+			if returned != nil {
+				return returned
+			}
+
+			return nil
+		case <-timer.C:
+			return ErrConfirmTimeout
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func pendingReturn(returns <-chan amqp.Return, messageID string) *ReturnedError {
+	for {
+		select {
+		case ret, ok := <-returns:
+			if !ok {
+				return nil
+			}
+
+			if isReturnOf(ret, messageID) {
+				return newReturnedError(ret)
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func isReturnOf(ret amqp.Return, messageID string) bool {
+	return messageID == "" || ret.MessageId == messageID
+}
+
+func newReturnedError(ret amqp.Return) *ReturnedError {
+	return &ReturnedError{
+		Exchange:   ret.Exchange,
+		RoutingKey: ret.RoutingKey,
+		ReplyCode:  ret.ReplyCode,
+		ReplyText:  ret.ReplyText,
+	}
+}
+
+// drain discards confirms and returns left behind by publishes that timed out.
+func drain(confirms <-chan amqp.Confirmation, returns <-chan amqp.Return) {
+	for {
+		select {
+		case _, ok := <-confirms:
+			if !ok {
+				return
+			}
+		case _, ok := <-returns:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
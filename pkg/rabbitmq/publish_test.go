@@ -0,0 +1,109 @@
+package rabbitmq
+
+import (
+	"context"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type PublishTestSuite struct {
+	suite.Suite
+	confirms chan amqp.Confirmation
+	returns  chan amqp.Return
+}
+
+func (suite *PublishTestSuite) SetupTest() {
+	suite.confirms = make(chan amqp.Confirmation, notifyBuffer)
+	suite.returns = make(chan amqp.Return, notifyBuffer)
+}
+
+func (suite *PublishTestSuite) wait(tag uint64, timeout time.Duration) error {
+	return waitForConfirm(context.Background(), tag, "message-id", suite.confirms, suite.returns, timeout)
+}
+
+func (suite *PublishTestSuite) TestPublish_Ack() {
+	suite.confirms <- amqp.Confirmation{DeliveryTag: 1, Ack: true}
+
+	suite.NoError(suite.wait(1, time.Second))
+}
+
+func (suite *PublishTestSuite) TestPublish_Nack() {
+	suite.confirms <- amqp.Confirmation{DeliveryTag: 1, Ack: false}
+
+	suite.ErrorIs(suite.wait(1, time.Second), ErrNacked)
+}
+
+func (suite *PublishTestSuite) TestPublish_SkipsStaleConfirms() {
+	suite.confirms <- amqp.Confirmation{DeliveryTag: 1, Ack: false}
+	suite.confirms <- amqp.Confirmation{DeliveryTag: 2, Ack: true}
+
+	suite.NoError(suite.wait(2, time.Second))
+}
+
+func (suite *PublishTestSuite) TestPublish_Returned() {
+	suite.returns <- amqp.Return{MessageId: "message-id", Exchange: "topics", RoutingKey: "user.create", ReplyCode: 312, ReplyText: "NO_ROUTE"}
+	suite.confirms <- amqp.Confirmation{DeliveryTag: 1, Ack: true}
+
+	err := suite.wait(1, time.Second)
+
+	var returned *ReturnedError
+	suite.ErrorAs(err, &returned)
+	suite.Equal("user.create", returned.RoutingKey)
+	suite.EqualValues(312, returned.ReplyCode)
+}
+
+func (suite *PublishTestSuite) TestPublish_IgnoresReturnsOfOtherMessages() {
+	suite.returns <- amqp.Return{MessageId: "other-id", ReplyCode: 312, ReplyText: "NO_ROUTE"}
+	suite.confirms <- amqp.Confirmation{DeliveryTag: 1, Ack: true}
+
+	suite.NoError(suite.wait(1, time.Second))
+}
+
+func (suite *PublishTestSuite) TestPublish_Timeout() {
+	suite.ErrorIs(suite.wait(1, 10*time.Millisecond), ErrConfirmTimeout)
+}
+
+func (suite *PublishTestSuite) TestPublish_ChannelClosed() {
+	close(suite.confirms)
+
+	suite.ErrorIs(suite.wait(1, time.Second), ErrChannelClosed)
+}
+
+func (suite *PublishTestSuite) TestPublish_ContextCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := waitForConfirm(ctx, 1, "message-id", suite.confirms, suite.returns, time.Second)
+
+	suite.ErrorIs(err, context.Canceled)
+}
+
+func (suite *PublishTestSuite) TestPublish_Drain() {
+	suite.confirms <- amqp.Confirmation{DeliveryTag: 1, Ack: true}
+	suite.returns <- amqp.Return{MessageId: "message-id"}
+
+	drain(suite.confirms, suite.returns)
+
+	suite.Empty(suite.confirms)
+	suite.Empty(suite.returns)
+}
+
+func TestUnit_PublishTestSuite(t *testing.T) {
+	suite.Run(t, new(PublishTestSuite))
+}
+
+type BackoffTestSuite struct {
+	suite.Suite
+}
+
+func (suite *BackoffTestSuite) TestBackoff_NextBackoff() {
+	suite.Equal(2*time.Second, nextBackoff(time.Second, time.Minute))
+	suite.Equal(time.Minute, nextBackoff(40*time.Second, time.Minute))
+}
+
+func TestUnit_BackoffTestSuite(t *testing.T) {
+	suite.Run(t, new(BackoffTestSuite))
+}
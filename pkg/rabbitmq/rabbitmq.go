@@ -1,26 +1,169 @@
 package rabbitmq
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"sync"
+	"time"
 	"user-service/config"
+	"user-service/pkg/logging"
 )
 
+const (
+	defaultConfirmTimeout      = 5 * time.Second
+	defaultReconnectBackoff    = time.Second
+	defaultMaxReconnectBackoff = 30 * time.Second
+
+	// notifyBuffer leaves room for confirms and returns of publishes that
+	// timed out, so they never block the connection until they are drained.
+	notifyBuffer = 16
+)
+
+var (
+	ErrNotConnected = errors.New("rabbitmq: not connected")
+	ErrClosed       = errors.New("rabbitmq: closed")
+)
+
+// RabbitMQ manages the connection to the broker. When the connection or the
+// publishing channel is lost it reconnects with exponential backoff and
+// declares the exchange again. It is safe for concurrent use.
 type RabbitMQ struct {
-	Connection *amqp.Connection
-	Channel    *amqp.Channel
+	url                 string
+	exchange            string
+	confirmTimeout      time.Duration
+	mandatory           bool
+	reconnectBackoff    time.Duration
+	maxReconnectBackoff time.Duration
+	logger              logging.Logger
+
+	// publishMutex serializes publishes, amqp channels are not safe for
+	// concurrent use and confirms are matched to the single waiting publish.
+	publishMutex sync.Mutex
+
+	// mutex guards the connection state below, which is replaced on reconnect.
+	mutex      sync.RWMutex
+	connection *amqp.Connection
+	channel    *amqp.Channel
+	confirms   chan amqp.Confirmation
+	returns    chan amqp.Return
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewRabbitMQ(cfg *config.Config, logger logging.Logger) (*RabbitMQ, error) {
+	r := &RabbitMQ{
+		url: fmt.Sprintf("amqp://%s:%s@%s:%d/",
+			cfg.RabbitMQ.User, cfg.RabbitMQ.Password, cfg.RabbitMQ.Host, cfg.RabbitMQ.Port),
+		exchange:            cfg.RabbitMQ.Exchange,
+		confirmTimeout:      cfg.RabbitMQ.ConfirmTimeout,
+		mandatory:           cfg.RabbitMQ.Mandatory,
+		reconnectBackoff:    cfg.RabbitMQ.ReconnectBackoff,
+		maxReconnectBackoff: cfg.RabbitMQ.MaxReconnectBackoff,
+		logger:              logger,
+		done:                make(chan struct{}),
+	}
+
+	if r.confirmTimeout <= 0 {
+		r.confirmTimeout = defaultConfirmTimeout
+	}
+
+	if r.reconnectBackoff <= 0 {
+		r.reconnectBackoff = defaultReconnectBackoff
+	}
+
+	if r.maxReconnectBackoff < r.reconnectBackoff {
+		r.maxReconnectBackoff = defaultMaxReconnectBackoff
+	}
+
+	closed, err := r.connect()
+
+	if err != nil {
+		return nil, err
+	}
+
+	go r.watch(closed)
+
+	return r, nil
+}
+
+// OpenChannel opens a new channel on the current connection, for consumers.
+// The caller owns the channel and must open a new one after a reconnect.
+func (r *RabbitMQ) OpenChannel() (*amqp.Channel, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.connection == nil || r.connection.IsClosed() {
+		return nil, ErrNotConnected
+	}
+
+	return r.connection.Channel()
 }
 
-func NewRabbitMQ(cfg *config.Config) (*RabbitMQ, error) {
-	connStr := fmt.Sprintf("amqp://%s:%s@%s:%d/",
-		cfg.RabbitMQ.User, cfg.RabbitMQ.Password, cfg.RabbitMQ.Host, cfg.RabbitMQ.Port)
+func (r *RabbitMQ) Close() {
+	r.closeOnce.Do(func() {
+		close(r.done)
+
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		if r.connection != nil {
+			_ = r.connection.Close()
+		}
+	})
+}
+
+// connect dials the broker, declares the exchange and opens the publishing
+// channel in confirm mode. The returned channel receives a value once either
+// the connection or the channel is closed.
+func (r *RabbitMQ) connect() (<-chan *amqp.Error, error) {
+	conn, err := amqp.Dial(r.url)
+
+	if err != nil {
+		return nil, err
+	}
 
-	conn, err := amqp.Dial(connStr)
+	channel, err := r.openPublishChannel(conn)
 
 	if err != nil {
+		_ = conn.Close()
 		return nil, err
 	}
 
+	closed := make(chan *amqp.Error, 1)
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	channelClosed := channel.NotifyClose(make(chan *amqp.Error, 1))
+
+	go func() {
+		select {
+		case err := <-connClosed:
+			closed <- err
+		case err := <-channelClosed:
+			closed <- err
+		}
+	}()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	select {
+	case <-r.done:
+		_ = conn.Close()
+		return nil, ErrClosed
+	default:
+	}
+
+	r.connection = conn
+	r.channel = channel
+	r.confirms = channel.NotifyPublish(make(chan amqp.Confirmation, notifyBuffer))
+	r.returns = channel.NotifyReturn(make(chan amqp.Return, notifyBuffer))
+
+	return closed, nil
+}
+
+func (r *RabbitMQ) openPublishChannel(conn *amqp.Connection) (*amqp.Channel, error) {
 	channel, err := conn.Channel()
 
 	if err != nil {
@@ -28,7 +171,7 @@ func NewRabbitMQ(cfg *config.Config) (*RabbitMQ, error) {
 	}
 
 	err = channel.ExchangeDeclare(
-		cfg.RabbitMQ.Exchange,
+		r.exchange,
 		"topic",
 		true,
 		false,
@@ -51,12 +194,84 @@ func NewRabbitMQ(cfg *config.Config) (*RabbitMQ, error) {
 		return nil, err
 	}
 
-	return &RabbitMQ{
-		Connection: conn,
-		Channel:    channel,
-	}, nil
+	err = channel.Confirm(false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return channel, nil
 }
 
-func (r *RabbitMQ) Close() {
-	_ = r.Connection.Close()
+// watch reconnects whenever the connection or the publishing channel is closed,
+// until Close is called.
+func (r *RabbitMQ) watch(closed <-chan *amqp.Error) {
+	for {
+		select {
+		case <-r.done:
+			return
+		case err := <-closed:
+			r.logger.Warning(context.Background(), "rabbitmq connection lost, reconnecting", "error", err)
+
+			r.disconnect()
+
+			closed = r.reconnect()
+
+			if closed == nil {
+				return
+			}
+
+			r.logger.Info(context.Background(), "rabbitmq connection restored")
+		}
+	}
+}
+
+func (r *RabbitMQ) disconnect() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.connection != nil {
+		_ = r.connection.Close()
+	}
+
+	r.connection = nil
+	r.channel = nil
+}
+
+// reconnect retries connecting with exponential backoff. It returns nil when
+// the RabbitMQ was closed in the meantime.
+func (r *RabbitMQ) reconnect() <-chan *amqp.Error {
+	delay := r.reconnectBackoff
+
+	for {
+		select {
+		case <-r.done:
+			return nil
+		case <-time.After(delay):
+		}
+
+		closed, err := r.connect()
+
+		if err == nil {
+			return closed
+		}
+
+		if errors.Is(err, ErrClosed) {
+			return nil
+		}
+
+		r.logger.Warning(context.Background(), "reconnecting to rabbitmq failed", "error", err, "retry_in", delay)
+
+		delay = nextBackoff(delay, r.maxReconnectBackoff)
+	}
+}
+
+func nextBackoff(delay, max time.Duration) time.Duration {
+	delay *= 2
+
+	if delay > max {
+		return max
+	}
+
+	return delay
 }
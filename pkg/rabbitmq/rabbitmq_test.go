@@ -0,0 +1,143 @@
+package rabbitmq
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/pkg/logging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type RabbitMQTestSuite struct {
+	suite.Suite
+	Cfg          *config.Config
+	TestRabbitMQ *RabbitMQ
+}
+
+func (suite *RabbitMQTestSuite) SetupSuite() {
+	cfgPath := "../../test/user.config"
+	cfg, err := config.UseConfig(cfgPath)
+
+	if err != nil {
+		panic(errors.WithStack(err))
+	}
+
+	cfg.RabbitMQ.Mandatory = false
+	cfg.RabbitMQ.ReconnectBackoff = 50 * time.Millisecond
+
+	rmq, err := NewRabbitMQ(cfg, logging.MockLogger{})
+
+	if err != nil {
+		panic(errors.WithStack(err))
+	}
+
+	suite.Cfg = cfg
+	suite.TestRabbitMQ = rmq
+}
+
+func (suite *RabbitMQTestSuite) TearDownSuite() {
+	suite.TestRabbitMQ.Close()
+}
+
+func (suite *RabbitMQTestSuite) publish() error {
+	return suite.TestRabbitMQ.Publish(context.Background(), suite.Cfg.RabbitMQ.Exchange, "user.test", amqp.Publishing{Body: []byte("test")})
+}
+
+func (suite *RabbitMQTestSuite) TestRabbitMQ_Reconnect() {
+	suite.NoError(suite.publish())
+
+	suite.TestRabbitMQ.mutex.RLock()
+	_ = suite.TestRabbitMQ.connection.Close()
+	suite.TestRabbitMQ.mutex.RUnlock()
+
+	suite.Eventually(func() bool {
+		return suite.publish() == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func (suite *RabbitMQTestSuite) TestRabbitMQ_ReopensClosedChannel() {
+	suite.TestRabbitMQ.mutex.RLock()
+	_ = suite.TestRabbitMQ.channel.Close()
+	suite.TestRabbitMQ.mutex.RUnlock()
+
+	suite.Eventually(func() bool {
+		return suite.publish() == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func (suite *RabbitMQTestSuite) TestRabbitMQ_ConcurrentPublish() {
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			errs <- suite.publish()
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		suite.NoError(err)
+	}
+}
+
+func (suite *RabbitMQTestSuite) TestRabbitMQ_DeadLetter() {
+	queue := "dead-letter-test"
+
+	channel, err := suite.TestRabbitMQ.OpenChannel()
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(DeclareQueue(channel, queue, 5))
+
+	defer func() {
+		_, _ = channel.QueueDelete(queue, false, false, false)
+		_, _ = channel.QueueDelete(DeadLetterName(queue), false, false, false)
+		_ = channel.ExchangeDelete(DeadLetterName(queue), false, false)
+		_ = channel.Close()
+	}()
+
+	delivery := amqp.Delivery{MessageId: "message-id", RoutingKey: "user.test", Body: []byte("test")}
+
+	err = suite.TestRabbitMQ.DeadLetter(context.Background(), queue, delivery, amqp.Table{"x-failure-reason": "test"})
+	suite.Require().NoError(err)
+
+	deliveries, err := suite.TestRabbitMQ.PeekDeadLetters(queue, 10)
+	suite.NoError(err)
+	suite.Require().Len(deliveries, 1)
+	suite.Equal("test", deliveries[0].Headers["x-failure-reason"])
+
+	found, err := suite.TestRabbitMQ.FindDeadLetter(queue, "message-id")
+	suite.NoError(err)
+	suite.Equal("user.test", found.RoutingKey)
+
+	err = suite.TestRabbitMQ.TakeDeadLetter(queue, "message-id", func(delivery amqp.Delivery) error {
+		return errors.New("handling failed")
+	})
+	suite.Error(err)
+
+	err = suite.TestRabbitMQ.TakeDeadLetter(queue, "message-id", func(delivery amqp.Delivery) error {
+		return nil
+	})
+	suite.NoError(err)
+
+	_, err = suite.TestRabbitMQ.FindDeadLetter(queue, "message-id")
+	suite.ErrorIs(err, ErrDeadLetterNotFound)
+
+	purged, err := suite.TestRabbitMQ.PurgeDeadLetters(queue)
+	suite.NoError(err)
+	suite.Zero(purged)
+}
+
+func TestIntegration_RabbitMQTestSuite(t *testing.T) {
+	suite.Run(t, new(RabbitMQTestSuite))
+}
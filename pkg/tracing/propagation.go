@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// propagator always uses W3C trace context on messages, regardless of the
+// global propagator, so consumers can rely on traceparent being present.
+var propagator = propagation.TraceContext{}
+
+// MapCarrier adapts message headers, such as an amqp.Table or Service Bus
+// application properties, to a propagation.TextMapCarrier.
+type MapCarrier map[string]interface{}
+
+func (carrier MapCarrier) Get(key string) string {
+	value, _ := carrier[key].(string)
+	return value
+}
+
+func (carrier MapCarrier) Set(key, value string) {
+	carrier[key] = value
+}
+
+func (carrier MapCarrier) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Inject writes the traceparent and tracestate of the span in ctx to headers.
+func Inject(ctx context.Context, headers map[string]interface{}) {
+	propagator.Inject(ctx, MapCarrier(headers))
+}
+
+// Extract returns ctx with the remote span context found in headers.
+func Extract(ctx context.Context, headers map[string]interface{}) context.Context {
+	return propagator.Extract(ctx, MapCarrier(headers))
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"github.com/stretchr/testify/suite"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type PropagationTestSuite struct {
+	suite.Suite
+	spanContext trace.SpanContext
+}
+
+func (suite *PropagationTestSuite) SetupSuite() {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+
+	suite.spanContext = trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+}
+
+func (suite *PropagationTestSuite) TestPropagation_Inject() {
+	ctx := trace.ContextWithSpanContext(context.Background(), suite.spanContext)
+	headers := map[string]interface{}{"other": 1}
+
+	Inject(ctx, headers)
+
+	suite.Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", headers["traceparent"])
+	suite.Equal(1, headers["other"])
+}
+
+func (suite *PropagationTestSuite) TestPropagation_Inject_NoSpan() {
+	headers := map[string]interface{}{}
+
+	Inject(context.Background(), headers)
+
+	suite.NotContains(headers, "traceparent")
+}
+
+func (suite *PropagationTestSuite) TestPropagation_Extract() {
+	headers := map[string]interface{}{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+
+	ctx := Extract(context.Background(), headers)
+
+	spanContext := trace.SpanContextFromContext(ctx)
+
+	suite.Equal(suite.spanContext.TraceID(), spanContext.TraceID())
+	suite.Equal(suite.spanContext.SpanID(), spanContext.SpanID())
+	suite.True(spanContext.IsRemote())
+}
+
+func (suite *PropagationTestSuite) TestPropagation_Extract_IgnoresNonStringValues() {
+	headers := map[string]interface{}{"traceparent": 42}
+
+	ctx := Extract(context.Background(), headers)
+
+	suite.False(trace.SpanContextFromContext(ctx).IsValid())
+}
+
+func TestUnit_PropagationTestSuite(t *testing.T) {
+	suite.Run(t, new(PropagationTestSuite))
+}